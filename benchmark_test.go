@@ -2,6 +2,7 @@ package fasttld
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/fatih/color"
@@ -78,6 +79,94 @@ func BenchmarkComparison(b *testing.B) {
 	}
 }
 
+func BenchmarkExtractValue(b *testing.B) {
+	benchmarkURL := "https://iupac.org/iupac-announces-the-2021-top-ten-emerging-technologies-in-chemistry/"
+	testPSLFilePath, _ := getTestPSLFilePath()
+	GoFastTld, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GoFastTld.ExtractValue(benchmarkURL)
+	}
+}
+
+func BenchmarkMatchSuffix(b *testing.B) {
+	testPSLFilePath, _ := getTestPSLFilePath()
+	GoFastTld, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, host := range []string{"www.example.co.uk", "a.example.b.ck", "a.www.ck"} {
+		benchmarkHost := host
+		b.Run(benchmarkHost, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				GoFastTld.MatchSuffix(benchmarkHost)
+			}
+		})
+	}
+}
+
+func BenchmarkExtractAll(b *testing.B) {
+	benchmarkURLs := make([]string, 100000)
+	for i := range benchmarkURLs {
+		benchmarkURLs[i] = fmt.Sprintf("https://www.domain%d.co.uk/path?page=%d", i, i%10)
+	}
+	testPSLFilePath, _ := getTestPSLFilePath()
+	GoFastTld, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	b.Run("Loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			results := make([]*ExtractResult, len(benchmarkURLs))
+			for idx, url := range benchmarkURLs {
+				if res, err := GoFastTld.Extract(URLParams{URL: url}); err == nil {
+					results[idx] = &res
+				}
+			}
+		}
+	})
+	b.Run("ExtractAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GoFastTld.ExtractAll(benchmarkURLs, 0)
+		}
+	})
+}
+
+func BenchmarkExtractCacheZipf(b *testing.B) {
+	// URLs drawn from a Zipfian distribution, as in web logs where a few domains dominate
+	const distinctURLs = 10000
+	urls := make([]string, distinctURLs)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://www.domain%d.co.uk/path?page=%d", i, i%10)
+	}
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, distinctURLs-1)
+	benchmarkURLs := make([]string, 1<<16)
+	for i := range benchmarkURLs {
+		benchmarkURLs[i] = urls[zipf.Uint64()]
+	}
+
+	testPSLFilePath, _ := getTestPSLFilePath()
+	for _, cacheSize := range []int{0, 100, 1000} {
+		GoFastTld, _ := New(SuffixListParams{
+			CacheFilePath: testPSLFilePath,
+			CacheSize:     cacheSize,
+		})
+		b.Run(fmt.Sprintf("CacheSize=%d", cacheSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				GoFastTld.Extract(URLParams{URL: benchmarkURLs[i%len(benchmarkURLs)]})
+			}
+		})
+	}
+}
+
 /*
 
 Omitted modules
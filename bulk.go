@@ -0,0 +1,244 @@
+package fasttld
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidateZone checks that every owner name in zone file r is a registered domain
+// directly under tld, and returns the lines that are not.
+//
+// A line is returned if its owner name cannot be extracted, has a SubDomain,
+// or has a Suffix other than tld. Comments, directives other than $ORIGIN,
+// lines continuing the previous owner name, and records for tld itself are skipped.
+// Relative owner names are resolved against $ORIGIN, if any.
+func (f *FastTLD) ValidateZone(r io.Reader, tld string) []string {
+	var invalidLines []string
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	var origin string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if commentIdx := strings.IndexByte(line, ';'); commentIdx != -1 {
+			line = line[0:commentIdx]
+		}
+		if len(strings.TrimSpace(line)) == 0 || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		fields := strings.Fields(line)
+		owner := fields[0]
+		if strings.HasPrefix(owner, "$") {
+			if owner == "$ORIGIN" && len(fields) > 1 {
+				origin = strings.Trim(fields[1], ".")
+			}
+			continue
+		}
+		switch {
+		case owner == "@":
+			owner = origin
+		case !strings.HasSuffix(owner, ".") && len(origin) != 0:
+			owner = owner + "." + origin
+		}
+		owner = strings.ToLower(strings.TrimSuffix(owner, "."))
+		if owner == tld {
+			continue
+		}
+		res, err := f.Extract(URLParams{URL: owner})
+		if err != nil || res.Suffix != tld || len(res.SubDomain) != 0 {
+			invalidLines = append(invalidLines, scanner.Text())
+		}
+	}
+	return invalidLines
+}
+
+// ExtractAllURLParams extracts components from every URL embedded as a query parameter
+// value in rawURL (e.g. redirect and tracking URLs), keyed by parameter name.
+//
+// Parameter values are URL-decoded before extraction. Values without a URL scheme
+// (or a leading "//") and values that cannot be extracted are skipped.
+// If a parameter has multiple URL values, only the first one is used.
+func (f *FastTLD) ExtractAllURLParams(rawURL string) map[string]ExtractResult {
+	results := make(map[string]ExtractResult)
+	if fragmentIdx := strings.IndexByte(rawURL, '#'); fragmentIdx != -1 {
+		rawURL = rawURL[0:fragmentIdx]
+	}
+	queryStartIdx := strings.IndexByte(rawURL, '?')
+	if queryStartIdx == -1 {
+		return results
+	}
+	query := rawURL[queryStartIdx+1:]
+	// ParseQuery returns all parameters that could be parsed, even if there are errors
+	params, _ := url.ParseQuery(query)
+	for key, values := range params {
+		for _, value := range values {
+			if getSchemeEndIndex(value) == -1 {
+				continue
+			}
+			if res, err := f.Extract(URLParams{URL: value}); err == nil {
+				results[key] = res
+				break
+			}
+		}
+	}
+	return results
+}
+
+// defaultMaxRedirectHops is the number of embedded URLs followed by ExtractRedirectChain()
+// if maxHops is not positive.
+const defaultMaxRedirectHops int = 10
+
+// firstEmbeddedURL returns the first URL-decoded query parameter value in path
+// with a URL scheme (or a leading "//"), or an empty string if there is none.
+func firstEmbeddedURL(path string) string {
+	if fragmentIdx := strings.IndexByte(path, '#'); fragmentIdx != -1 {
+		path = path[0:fragmentIdx]
+	}
+	queryStartIdx := strings.IndexByte(path, '?')
+	if queryStartIdx == -1 {
+		return ""
+	}
+	for _, param := range strings.Split(path[queryStartIdx+1:], "&") {
+		_, value, _ := strings.Cut(param, "=")
+		if value, err := url.QueryUnescape(value); err == nil && getSchemeEndIndex(value) != -1 {
+			return value
+		}
+	}
+	return ""
+}
+
+// ExtractRedirectChain extracts components from rawURL, and then from the first URL embedded
+// as a query parameter value of each extracted URL in turn, to unwind a chain of redirects
+// encoded in query parameters, e.g. "https://a.com/?next=https%3A%2F%2Fb.com%2F%3Fnext%3D...".
+//
+// At most maxHops embedded URLs are followed, or 10 if maxHops is not positive.
+// The chain ends at the first URL that cannot be extracted, which is not included.
+func (f *FastTLD) ExtractRedirectChain(rawURL string, maxHops int) []ExtractResult {
+	if maxHops <= 0 {
+		maxHops = defaultMaxRedirectHops
+	}
+	var chain []ExtractResult
+	for hop := 0; hop <= maxHops && len(rawURL) != 0; hop++ {
+		res, err := f.Extract(URLParams{URL: rawURL})
+		if err != nil {
+			break
+		}
+		chain = append(chain, res)
+		rawURL = firstEmbeddedURL(res.Path)
+	}
+	return chain
+}
+
+// ExtractList extracts components from each host or URL in s, a list separated by any of the
+// characters in seps, e.g. "," or ", " for the values of CSP and CORS configuration directives.
+// If seps is empty, s is split on whitespace.
+//
+// Whitespace around each entry is trimmed. Empty entries and entries that cannot be extracted
+// (e.g. the CSP keyword 'self') are skipped.
+func (f *FastTLD) ExtractList(s string, seps string) []ExtractResult {
+	isSep := func(r rune) bool {
+		if len(seps) == 0 {
+			return whitespaceRuneSet.Exists(r)
+		}
+		return strings.ContainsRune(seps, r)
+	}
+	var results []ExtractResult
+	for _, entry := range strings.FieldsFunc(s, isSep) {
+		entry = fastTrim(entry, whitespaceRuneSet, trimBoth)
+		if len(entry) == 0 {
+			continue
+		}
+		if res, err := f.Extract(URLParams{URL: entry}); err == nil {
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// extractAllChunkSize is the number of URLs claimed at a time by each worker of ExtractAll().
+const extractAllChunkSize int = 256
+
+// ExtractAll extracts components from each of urls with default URLParams, using up to workers
+// goroutines, or runtime.GOMAXPROCS(0) goroutines if workers is not positive.
+//
+// The i-th result is for urls[i], and is nil if urls[i] is invalid.
+// Each worker extracts into its own scratch ExtractResult and copies valid results into
+// a single backing array, and the results are merged into the returned slice once all
+// workers are done, so that there is no allocation per URL.
+// ExtractAll is safe to call concurrently with Update(), but URLs extracted before and
+// after the suffix list is replaced use different suffix lists.
+func (f *FastTLD) ExtractAll(urls []string, workers int) []*ExtractResult {
+	values := make([]ExtractResult, len(urls))
+	valid := make([]bool, len(urls))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := (len(urls) + extractAllChunkSize - 1) / extractAllChunkSize; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	var next atomic.Int64 // start index of the next chunk of urls
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var scratch ExtractResult
+			var err error
+			for {
+				start := int(next.Add(int64(extractAllChunkSize))) - extractAllChunkSize
+				if start >= len(urls) {
+					return
+				}
+				end := start + extractAllChunkSize
+				if end > len(urls) {
+					end = len(urls)
+				}
+				for idx := start; idx < end; idx++ {
+					if scratch, err = f.Extract(URLParams{URL: urls[idx]}); err == nil {
+						values[idx], valid[idx] = scratch, true
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	results := make([]*ExtractResult, len(urls))
+	for idx := range values {
+		if valid[idx] {
+			results[idx] = &values[idx]
+		}
+	}
+	return results
+}
+
+// DedupByOrigin returns urls without URLs having the same origin (see ExtractResult.OriginKey)
+// as an earlier URL, preserving the order in which they first appear.
+//
+// Invalid URLs are passed through unchanged, and only removed if they are exact duplicates
+// of an earlier invalid URL.
+func (f *FastTLD) DedupByOrigin(urls []string) []string {
+	deduped := make([]string, 0, len(urls))
+	seenOrigins := make(map[string]struct{}, len(urls))
+	seenInvalidURLs := make(map[string]struct{})
+	for _, url := range urls {
+		seen := seenOrigins
+		key := url
+		if res, err := f.Extract(URLParams{URL: url}); err == nil {
+			key = res.OriginKey()
+		} else {
+			seen = seenInvalidURLs
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, url)
+	}
+	return deduped
+}
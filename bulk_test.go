@@ -0,0 +1,218 @@
+package fasttld
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testZone = `$ORIGIN co.uk.
+$TTL 86400
+@            IN SOA ns1.nic.uk. hostmaster.nic.uk. ( 1 7200 900 1209600 86400 )
+             IN NS  ns1.nic.uk.
+; registered domains
+example      IN NS  ns1.example.net.
+             IN NS  ns2.example.net.
+Example2.co.uk. 3600 IN NS ns1.example.net.
+www.example  IN A   192.0.2.1
+other.org.   IN NS  ns1.example.net.
+example.com. IN NS  ns1.example.net.
+bad!name     IN NS  ns1.example.net.
+`
+
+func TestValidateZone(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	expected := []string{
+		"www.example  IN A   192.0.2.1",
+		"other.org.   IN NS  ns1.example.net.",
+		"example.com. IN NS  ns1.example.net.",
+		"bad!name     IN NS  ns1.example.net.",
+	}
+	if output := extractor.ValidateZone(strings.NewReader(testZone), "co.uk."); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Output %q not equal to expected %q", output, expected)
+	}
+	if output := extractor.ValidateZone(strings.NewReader(""), "co.uk"); len(output) != 0 {
+		t.Errorf("Output %q should be empty", output)
+	}
+}
+
+func TestExtractAllURLParams(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	rawURL := "https://tracker.example.com/click?id=42&next=https%3A%2F%2Fwww.example.co.uk%2Fa%3Fb%3Dc" +
+		"&fallback=http://shop.example.org:8080/cart&ref=example.net&bad=https%3A%2F%2Fexample%21.com#top"
+	expected := map[string]ExtractResult{
+		"next": {Scheme: "https://", SubDomain: "www", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+			RegisteredDomain: "example.co.uk", Path: "/a?b=c", HostType: HostName},
+		"fallback": {Scheme: "http://", SubDomain: "shop", Domain: "example", Suffix: "org", SuffixSection: ICANNSection,
+			RegisteredDomain: "example.org", Port: "8080", Path: "/cart", HostType: HostName},
+	}
+	if output := extractor.ExtractAllURLParams(rawURL); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Output %+v not equal to expected %+v", output, expected)
+	}
+
+	for _, rawURL := range []string{"", "https://example.com", "https://example.com/?a=1&b=example.com", "https://example.com/#?a=https://example.org"} {
+		if output := extractor.ExtractAllURLParams(rawURL); len(output) != 0 {
+			t.Errorf("%q | Output %+v should be empty", rawURL, output)
+		}
+	}
+}
+
+func TestExtractRedirectChain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	rawURL := "https://tracker.example.com/click?id=42&url=https%3A%2F%2Fgo.example.co.uk%2Fr%3Fnext%3Dhttps%253A%252F%252Flogin.example.net%252Fsignin"
+	registeredDomains := []string{"example.com", "example.co.uk", "example.net"}
+	for _, test := range []struct {
+		maxHops  int
+		expected []string
+	}{
+		{0, registeredDomains},
+		{2, registeredDomains},
+		{1, registeredDomains[0:2]},
+	} {
+		var output []string
+		for _, res := range extractor.ExtractRedirectChain(rawURL, test.maxHops) {
+			output = append(output, res.RegisteredDomain)
+		}
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("maxHops %d | Output %q not equal to expected %q", test.maxHops, output, test.expected)
+		}
+	}
+
+	chain := extractor.ExtractRedirectChain(rawURL, 0)
+	if len(chain) != 3 || chain[2].Path != "/signin" {
+		t.Errorf("Expected last hop to have Path /signin. Got %+v.", chain)
+	}
+
+	for rawURL, expected := range map[string]int{
+		"": 0,
+		"https://example!.com/?u=https://example.com":         0,
+		"https://example.com/?a=1&b=example.org":              1,
+		"https://example.com/?u=https%3A%2F%2Fexample%21.org": 1,
+		"https://example.com/#?u=https://example.org":         1,
+	} {
+		if output := extractor.ExtractRedirectChain(rawURL, 0); len(output) != expected {
+			t.Errorf("%q | Output %+v should have length %d", rawURL, output, expected)
+		}
+	}
+}
+
+func TestExtractList(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	for _, test := range []struct {
+		s        string
+		seps     string
+		expected []string
+	}{
+		{"example.com, www.münchen.de ,https://api.example.co.uk", ",", []string{"example.com", "münchen.de", "example.co.uk"}},
+		{"https://example.com https://*.example.org\t'self'  ", "", []string{"example.com", "example.org"}},
+		{"https://a.example.com, https://b.example.net", ", ", []string{"example.com", "example.net"}},
+		{"a.example.com;;b.example.net;", ";", []string{"example.com", "example.net"}},
+		{"example!.com,example.com", ",", []string{"example.com"}},
+		{" , ,", ",", nil},
+		{"", ",", nil},
+	} {
+		var output []string
+		for _, res := range extractor.ExtractList(test.s, test.seps) {
+			output = append(output, res.RegisteredDomain)
+		}
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("%q split on %q | Output %q not equal to expected %q", test.s, test.seps, output, test.expected)
+		}
+	}
+
+	results := extractor.ExtractList("www.example.com:8443, http://[::1]:80", ",")
+	if len(results) != 2 || results[0].Port != "8443" || results[1].HostType != IPv6 {
+		t.Errorf("Expected port and IPv6 host to be extracted. Got %+v.", results)
+	}
+}
+
+func TestDedupByOrigin(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	urls := []string{
+		"https://www.example.com/a",
+		"HTTPS://WWW.EXAMPLE.COM:443/b",
+		"https://user@www.Example.com/c?d=e",
+		"http://www.example.com/a",
+		"http://www.example.com:80/a",
+		"https://www.example.com:8443/a",
+		"https://example!.com",
+		"https://example.com",
+		"https://example!.com",
+		"https://Example.COM.",
+	}
+	expected := []string{
+		"https://www.example.com/a",
+		"http://www.example.com/a",
+		"https://www.example.com:8443/a",
+		"https://example!.com",
+		"https://example.com",
+	}
+	if output := extractor.DedupByOrigin(urls); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Output %q not equal to expected %q", output, expected)
+	}
+	if output := extractor.DedupByOrigin(nil); len(output) != 0 {
+		t.Errorf("Output %q should be empty", output)
+	}
+}
+
+func TestExtractAll(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+
+	var urls []string
+	for i := 0; i < 1000; i++ {
+		switch i % 4 {
+		case 0:
+			urls = append(urls, fmt.Sprintf("https://www%d.example.co.uk/path", i))
+		case 1:
+			urls = append(urls, fmt.Sprintf("http://10.0.%d.%d:8080", i/256, i%256))
+		case 2:
+			urls = append(urls, fmt.Sprintf("https://example%d!.com", i))
+		default:
+			urls = append(urls, fmt.Sprintf("user@sub.domain%d.münchen.de", i))
+		}
+	}
+	expected := make([]*ExtractResult, len(urls))
+	for i, url := range urls {
+		if res, err := extractor.Extract(URLParams{URL: url}); err == nil {
+			expected[i] = &res
+		}
+	}
+	for _, workers := range []int{-1, 0, 1, 3, 100} {
+		if output := extractor.ExtractAll(urls, workers); !reflect.DeepEqual(output, expected) {
+			t.Errorf("workers %d | Output not equal to expected", workers)
+		}
+	}
+	if output := extractor.ExtractAll(nil, 4); len(output) != 0 {
+		t.Errorf("Output %+v should be empty", output)
+	}
+}
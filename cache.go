@@ -0,0 +1,130 @@
+package fasttld
+
+import (
+	"container/list"
+	"sync"
+)
+
+// resultCacheKey contains the URLParams fields which affect the result of Extract(),
+// except WildcardResolver, which is not comparable.
+type resultCacheKey struct {
+	URL                      string
+	IgnoreSubDomains         bool
+	ConvertURLToPunyCode     bool
+	ConvertURLToUnicode      bool
+	PreserveSeparators       bool
+	DomainCase               DomainCase
+	StrictBidi               bool
+	BestEffort               bool
+	RequireScheme            bool
+	SortQueryParams          bool
+	InternSuffixes           bool
+	RejectWildcardHost       bool
+	MapHomoglyphSeparators   bool
+	UnknownSuffixPlaceholder string
+	SCPSyntax                bool
+	LabelSeparators          string
+	IncludeAuthority         bool
+	NormalizePortDigits      bool
+	StrictTrailingDot        bool
+	ValidatePort             bool
+	SplitPath                bool
+	SplitFragment            bool
+	Compatibility            Compatibility
+}
+
+func newResultCacheKey(e URLParams) resultCacheKey {
+	return resultCacheKey{
+		URL:                      e.URL,
+		IgnoreSubDomains:         e.IgnoreSubDomains,
+		ConvertURLToPunyCode:     e.ConvertURLToPunyCode,
+		ConvertURLToUnicode:      e.ConvertURLToUnicode,
+		PreserveSeparators:       e.PreserveSeparators,
+		DomainCase:               e.DomainCase,
+		StrictBidi:               e.StrictBidi,
+		BestEffort:               e.BestEffort,
+		RequireScheme:            e.RequireScheme,
+		SortQueryParams:          e.SortQueryParams,
+		InternSuffixes:           e.InternSuffixes,
+		RejectWildcardHost:       e.RejectWildcardHost,
+		MapHomoglyphSeparators:   e.MapHomoglyphSeparators,
+		UnknownSuffixPlaceholder: e.UnknownSuffixPlaceholder,
+		SCPSyntax:                e.SCPSyntax,
+		LabelSeparators:          e.LabelSeparators,
+		IncludeAuthority:         e.IncludeAuthority,
+		NormalizePortDigits:      e.NormalizePortDigits,
+		StrictTrailingDot:        e.StrictTrailingDot,
+		ValidatePort:             e.ValidatePort,
+		SplitPath:                e.SplitPath,
+		SplitFragment:            e.SplitFragment,
+		Compatibility:            e.Compatibility,
+	}
+}
+
+type resultCacheEntry struct {
+	key resultCacheKey
+	res ExtractResult
+	err error
+}
+
+// resultCache is a least recently used cache of Extract() results.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[resultCacheKey]*list.Element
+	order   *list.List // most recently used entry first
+	// generation is incremented on purge, so that results extracted
+	// with the previous suffix list are not added afterwards
+	generation uint64
+}
+
+// newResultCache returns a resultCache holding up to size results, or nil if size <= 0.
+func newResultCache(size int) *resultCache {
+	if size <= 0 {
+		return nil
+	}
+	return &resultCache{size: size, entries: make(map[resultCacheKey]*list.Element), order: list.New()}
+}
+
+// get returns the cached entry for key, or nil if there is none, and the current generation of c.
+func (c *resultCache) get(key resultCacheKey) (*resultCacheEntry, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*resultCacheEntry), c.generation
+	}
+	return nil, c.generation
+}
+
+// add caches the result for key, evicting the least recently used result if c is full.
+// The result is discarded if c has been purged since generation.
+func (c *resultCache) add(key resultCacheKey, generation uint64, res ExtractResult, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&resultCacheEntry{key: key, res: res, err: err})
+}
+
+// purge removes all cached results. It is a no-op if c is nil.
+func (c *resultCache) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[resultCacheKey]*list.Element)
+	c.order.Init()
+	c.generation++
+}
@@ -0,0 +1,123 @@
+package fasttld
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResultCacheEviction(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath, CacheSize: 2})
+	for _, url := range []string{"a.example.com", "b.example.com", "a.example.com", "c.example.com"} {
+		extractor.Extract(URLParams{URL: url})
+	}
+	for url, cached := range map[string]bool{"a.example.com": true, "b.example.com": false, "c.example.com": true} {
+		if entry, _ := extractor.resultCache.get(newResultCacheKey(URLParams{URL: url})); (entry != nil) != cached {
+			t.Errorf("%q | Expected cached = %t", url, cached)
+		}
+	}
+	if extractor.resultCache.order.Len() != 2 || len(extractor.resultCache.entries) != 2 {
+		t.Errorf("Expected 2 cached results. Got %d.", extractor.resultCache.order.Len())
+	}
+
+	// URLParams other than URL are part of the cache key
+	res, _ := extractor.Extract(URLParams{URL: "a.example.com", IgnoreSubDomains: true})
+	if res.SubDomain != "" {
+		t.Errorf("Expected result for IgnoreSubDomains = true. Got SubDomain %q.", res.SubDomain)
+	}
+
+	// URLParams with a WildcardResolver are not cached
+	extractor.Extract(URLParams{URL: "www.example.ck", WildcardResolver: func(base, label string) bool { return true }})
+	if entry, _ := extractor.resultCache.get(newResultCacheKey(URLParams{URL: "www.example.ck"})); entry != nil {
+		t.Errorf("Result with WildcardResolver should not be cached")
+	}
+
+	// Modifying the suffix list clears the cache
+	extractor.AddSuffix("a.example.com")
+	if len(extractor.resultCache.entries) != 0 {
+		t.Errorf("Cache should be cleared by AddSuffix")
+	}
+	if res, _ := extractor.Extract(URLParams{URL: "www.a.example.com"}); res.Suffix != "a.example.com" {
+		t.Errorf("Expected Suffix %q. Got %q.", "a.example.com", res.Suffix)
+	}
+	extractor.RemoveSuffix("a.example.com")
+	if res, _ := extractor.Extract(URLParams{URL: "www.a.example.com"}); res.Suffix != "com" {
+		t.Errorf("Expected Suffix %q. Got %q.", "com", res.Suffix)
+	}
+
+	// A purge discards results extracted with the previous suffix list
+	entry, generation := extractor.resultCache.get(newResultCacheKey(URLParams{URL: "d.example.com"}))
+	extractor.resultCache.purge()
+	extractor.resultCache.add(newResultCacheKey(URLParams{URL: "d.example.com"}), generation, ExtractResult{}, nil)
+	if entry, _ = extractor.resultCache.get(newResultCacheKey(URLParams{URL: "d.example.com"})); entry != nil {
+		t.Errorf("Result extracted before purge should not be cached")
+	}
+
+	if extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath}); extractor.resultCache != nil {
+		t.Errorf("Cache should be disabled by default")
+	}
+}
+
+func TestExtractCache(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+		CacheSize:            16,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+		CacheSize:            16,
+	})
+	for _, testCollection := range [][]extractTest{
+		schemeTests,
+		noSchemeTests,
+		ipv6Tests,
+		privateSuffixTests,
+		invalidTests,
+		bestEffortTests,
+		authorityTests,
+	} {
+		// extract each URL twice, so that the second result is from the cache
+		for _, test := range append(testCollection, testCollection...) {
+			extractor := extractorWithoutPrivateSuffix
+			if test.includePrivateSuffix {
+				extractor = extractorWithPrivateSuffix
+			}
+			res, err := extractor.Extract(test.urlParams)
+			if !reflect.DeepEqual(res, test.expected) {
+				t.Errorf("%+q | Output %+v not equal to expected output %+v | %q",
+					test.urlParams.URL, res, test.expected, test.description)
+			}
+			if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+				t.Errorf("%+q | Error %v not equal to expected error %v | %q",
+					test.urlParams.URL, err, test.err, test.description)
+			}
+		}
+	}
+}
+
+func TestResultCacheKeyFields(t *testing.T) {
+	// every URLParams field affecting the result must be part of the cache key
+	keyType := reflect.TypeOf(resultCacheKey{})
+	paramsType := reflect.TypeOf(URLParams{})
+	for i := 0; i < paramsType.NumField(); i++ {
+		field := paramsType.Field(i)
+		if field.Name == "WildcardResolver" {
+			continue
+		}
+		if keyField, ok := keyType.FieldByName(field.Name); !ok || keyField.Type != field.Type {
+			t.Errorf("URLParams.%s is not in resultCacheKey", field.Name)
+		}
+	}
+	if keyType.NumField() != paramsType.NumField()-1 {
+		t.Errorf("resultCacheKey has fields which are not in URLParams")
+	}
+}
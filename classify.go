@@ -0,0 +1,134 @@
+package fasttld
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TLDType indicates the category of the top-level domain of an extracted hostname.
+type TLDType int
+
+// NoTLD, CountryCodeTLD, GenericTLD, InfrastructureTLD and SpecialUseTLD indicate whether
+// the top-level domain is a country code TLD (e.g. uk), a generic TLD (e.g. com), the
+// infrastructure TLD arpa, a special-use domain name (e.g. test), or whether there is no hostname.
+const (
+	NoTLD TLDType = iota
+	CountryCodeTLD
+	GenericTLD
+	InfrastructureTLD
+	SpecialUseTLD
+)
+
+// specialUseTLDs are top-level special-use domain names reserved by IETF RFC 2606,
+// RFC 6761, RFC 6762, RFC 7686 and RFC 9476.
+var specialUseTLDs = map[string]struct{}{
+	"alt":       {},
+	"example":   {},
+	"invalid":   {},
+	"local":     {},
+	"localhost": {},
+	"onion":     {},
+	"test":      {},
+}
+
+// tld returns the top-level domain of r in lower case, or an empty string if r is not a hostname.
+func (r *ExtractResult) tld() string {
+	if r.HostType != HostName {
+		return ""
+	}
+	host := strings.ToLower(r.Host())
+	return host[strings.LastIndexByte(host, '.')+1:]
+}
+
+// TLDType returns the category of the top-level domain of r, which need not be in the Public Suffix List.
+//
+// Two-letter top-level domains and internationalised top-level domains that are not
+// new gTLDs (see IsNewGTLD) are country code TLDs.
+func (r *ExtractResult) TLDType() TLDType {
+	tld := r.tld()
+	if len(tld) == 0 {
+		return NoTLD
+	}
+	if _, ok := specialUseTLDs[tld]; ok {
+		return SpecialUseTLD
+	}
+	if tld == "arpa" {
+		return InfrastructureTLD
+	}
+	if len(tld) == 2 && 'a' <= tld[0] && tld[0] <= 'z' && 'a' <= tld[1] && tld[1] <= 'z' {
+		return CountryCodeTLD
+	}
+	if strings.HasPrefix(tld, "xn--") || utf8.RuneCountInString(tld) != len(tld) {
+		if _, ok := newGTLDs[tld]; !ok {
+			return CountryCodeTLD
+		}
+	}
+	return GenericTLD
+}
+
+// IsNewGTLD reports whether the top-level domain of r is a new gTLD delegated under the
+// ICANN New gTLD Program from 2013 onwards (e.g. xyz), as opposed to a legacy gTLD (e.g. com)
+// or a country code TLD (e.g. uk).
+//
+// New gTLDs are taken from a table generated from the Public Suffix List, regardless of the list used for extraction.
+func (r *ExtractResult) IsNewGTLD() bool {
+	if len(r.Suffix) == 0 {
+		return false
+	}
+	tld := r.tld()
+	_, ok := newGTLDs[tld]
+	return ok
+}
+
+// freeHostingSuffixes are PRIVATE section suffixes of free web hosting providers,
+// under which anyone can publish a site without registering a domain.
+var freeHostingSuffixes = map[string]struct{}{
+	"appspot.com":       {},
+	"azurewebsites.net": {},
+	"firebaseapp.com":   {},
+	"fly.dev":           {},
+	"github.io":         {},
+	"gitlab.io":         {},
+	"glitch.me":         {},
+	"herokuapp.com":     {},
+	"netlify.app":       {},
+	"onrender.com":      {},
+	"pages.dev":         {},
+	"repl.co":           {},
+	"vercel.app":        {},
+	"web.app":           {},
+	"wixsite.com":       {},
+}
+
+// IsFreeHosting reports whether the registered domain of r is under the PRIVATE section
+// suffix of a free web hosting provider, e.g. "user.github.io" or "example.blogspot.co.uk".
+//
+// The extractor must include private suffixes (see SuffixListParams.IncludePrivateSuffix).
+func (r *ExtractResult) IsFreeHosting() bool {
+	if r.SuffixSection != PrivateSection || len(r.RegisteredDomain) == 0 {
+		return false
+	}
+	suffix := strings.ToLower(labelSeparatorReplacer.Replace(r.Suffix))
+	if strings.HasPrefix(suffix, "blogspot.") {
+		// blogspot.com and its country code variants, e.g. blogspot.co.uk
+		return true
+	}
+	_, ok := freeHostingSuffixes[suffix]
+	return ok
+}
+
+// IsNumericDomain reports whether the Domain label of the registered domain of r is
+// entirely numeric (ASCII digits), e.g. "123" for "123.co.uk".
+//
+// This is false for IP addresses, and for hostnames without a registered domain.
+func (r *ExtractResult) IsNumericDomain() bool {
+	if r.HostType != HostName || len(r.Domain) == 0 || len(r.RegisteredDomain) == 0 {
+		return false
+	}
+	for i := 0; i < len(r.Domain); i++ {
+		if !numericSet.contains(r.Domain[i]) {
+			return false
+		}
+	}
+	return true
+}
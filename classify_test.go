@@ -0,0 +1,128 @@
+package fasttld
+
+import "testing"
+
+var isNewGTLDTests = map[string]bool{
+	"https://www.example.com":                 false,
+	"https://example.co.uk":                   false,
+	"https://example.info":                    false,
+	"https://example.中国":                      false,
+	"https://example.xyz":                     true,
+	"https://www.Example.XYZ":                 true,
+	"https://example.blog":                    true,
+	"https://example.公司":                      true,
+	"https://example.xn--55qx5d":              true,
+	"https://example．xyz":                     true,
+	"https://example.this-tld-cannot-be-real": false,
+	"https://127.0.0.1":                       false,
+	"https://[::1]":                           false,
+}
+
+func TestIsNewGTLD(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for url, expected := range isNewGTLDTests {
+		res, _ := extractor.Extract(URLParams{URL: url})
+		if output := res.IsNewGTLD(); output != expected {
+			t.Errorf("%q | Output %t not equal to expected %t", url, output, expected)
+		}
+	}
+}
+
+var tldTypeTests = map[string]TLDType{
+	"https://www.example.co.uk":               CountryCodeTLD,
+	"https://example.DE":                      CountryCodeTLD,
+	"https://example.中国":                      CountryCodeTLD,
+	"https://example.xn--fiqs8s":              CountryCodeTLD,
+	"https://www.example.com":                 GenericTLD,
+	"https://example.xyz":                     GenericTLD,
+	"https://example.公司":                      GenericTLD,
+	"https://example.this-tld-cannot-be-real": GenericTLD,
+	"1.0.0.127.in-addr.arpa":                  InfrastructureTLD,
+	"https://example.test":                    SpecialUseTLD,
+	"https://www.example.invalid.":            SpecialUseTLD,
+	"http://localhost:8080":                   SpecialUseTLD,
+	"http://example.onion":                    SpecialUseTLD,
+	"https://127.0.0.1":                       NoTLD,
+	"https://[::1]":                           NoTLD,
+	"https://example!.com":                    NoTLD,
+}
+
+func TestTLDType(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for url, expected := range tldTypeTests {
+		res, _ := extractor.Extract(URLParams{URL: url})
+		if output := res.TLDType(); output != expected {
+			t.Errorf("%q | Output %d not equal to expected %d", url, output, expected)
+		}
+	}
+}
+
+var isFreeHostingTests = map[string]bool{
+	"https://x.github.io":              true,
+	"https://a.b.x.github.io/path":     true,
+	"https://X.GitHub.IO":              true,
+	"https://example.blogspot.com":     true,
+	"https://example.blogspot.co.uk":   true,
+	"https://example.herokuapp.com":    true,
+	"https://x.example.com":            false,
+	"https://github.io":                false,
+	"https://example.s3.amazonaws.com": false,
+	"https://127.0.0.1":                false,
+}
+
+func TestIsFreeHosting(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath, IncludePrivateSuffix: true})
+	for url, expected := range isFreeHostingTests {
+		res, _ := extractor.Extract(URLParams{URL: url})
+		if output := res.IsFreeHosting(); output != expected {
+			t.Errorf("%q | Output %t not equal to expected %t", url, output, expected)
+		}
+	}
+
+	icannExtractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	res, _ := icannExtractor.Extract(URLParams{URL: "https://x.github.io"})
+	if res.IsFreeHosting() {
+		t.Errorf("Expected IsFreeHosting false without private suffixes")
+	}
+}
+
+var isNumericDomainTests = map[string]bool{
+	"https://123.co.uk":     true,
+	"https://www.123.co.uk": true,
+	"https://0.com":         true,
+	"https://abc.co.uk":     false,
+	"https://123abc.co.uk":  false,
+	"https://12-34.co.uk":   false,
+	"https://123.456.co.uk": true,
+	"https://127.0.0.1":     false,
+	"https://1.2.3":         false,
+	"https://[::1]":         false,
+	"https://co.uk":         false,
+	"https://１２３.co.uk":     false,
+}
+
+func TestIsNumericDomain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for url, expected := range isNumericDomainTests {
+		res, _ := extractor.Extract(URLParams{URL: url})
+		if output := res.IsNumericDomain(); output != expected {
+			t.Errorf("%q | Output %t not equal to expected %t", url, output, expected)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package fasttld
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/elliotwutingfeng/go-fasttld"
@@ -8,7 +9,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var includePrivateSuffix, ignoreSubDomains, toPunyCode bool
+var includePrivateSuffix, ignoreSubDomains, toPunyCode, toJSON bool
 
 var extractCmd = &cobra.Command{
 	Use:     "extract",
@@ -32,6 +33,10 @@ fasttld extract abc.example.com:5000/a/path
 			color.New(color.FgHiRed, color.Bold).Print("Error: ")
 			color.New(color.FgHiWhite).Println(err)
 		}
+		if toJSON {
+			fmt.Println(string(res.ToJSONIndent("", "  ")))
+			return
+		}
 		fasttld.PrintRes(args[0], res)
 	},
 }
@@ -40,5 +45,6 @@ func init() {
 	extractCmd.Flags().BoolVarP(&includePrivateSuffix, "private-suffix", "p", false, "Include private suffix")
 	extractCmd.Flags().BoolVarP(&ignoreSubDomains, "ignore-subdomains", "i", false, "Ignore subdomains")
 	extractCmd.Flags().BoolVarP(&toPunyCode, "to-punycode", "t", false, "Convert to punycode")
+	extractCmd.Flags().BoolVarP(&toJSON, "json", "j", false, "Print as JSON")
 	rootCmd.AddCommand(extractCmd)
 }
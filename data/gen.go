@@ -3,7 +3,7 @@
 //go:build ignore
 // +build ignore
 
-// This program generates fallback.go. It can be invoked by running
+// This program generates fallback.go and newgtlds.go. It can be invoked by running
 // go generate
 
 //go:generate go run gen.go
@@ -14,10 +14,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/exec"
+	"sort"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/spf13/afero"
+	"golang.org/x/net/idna"
 )
 
 func main() {
@@ -36,15 +40,64 @@ func main() {
 	fail(err)
 	defer f.Close()
 
+	timestamp := time.Now()
 	pslTemplate.Execute(f, struct {
 		Timestamp time.Time
 		URL       string
 		Content   string
 	}{
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 		URL:       url,
 		Content:   content,
 	})
+
+	g, err := os.Create("../newgtlds.go")
+	fail(err)
+	defer g.Close()
+
+	fail(newGTLDsTemplate.Execute(g, struct {
+		Timestamp time.Time
+		URL       string
+		TLDs      []string
+	}{
+		Timestamp: timestamp,
+		URL:       url,
+		TLDs:      newGTLDs(content),
+	}))
+	fail(exec.Command("gofmt", "-w", "../newgtlds.go").Run())
+}
+
+// newGTLDs returns the new gTLDs listed after the "// newGTLDs" comment
+// in the ICANN section of Public Suffix List content, in both Unicode and punycode form.
+func newGTLDs(content string) []string {
+	var tlds []string
+	sectionStartIdx := strings.Index(content, "// newGTLDs")
+	if sectionStartIdx == -1 {
+		log.Fatal("new gTLDs not found in Public Suffix List")
+	}
+	section := content[sectionStartIdx:]
+	if sectionEndIdx := strings.Index(section, "// ===END ICANN DOMAINS==="); sectionEndIdx != -1 {
+		section = section[0:sectionEndIdx]
+	}
+	seen := make(map[string]struct{})
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "//") || strings.Contains(line, ".") {
+			continue
+		}
+		forms := []string{line}
+		if asPunyCode, err := idna.ToASCII(line); err == nil {
+			forms = append(forms, asPunyCode)
+		}
+		for _, tld := range forms {
+			if _, ok := seen[tld]; !ok {
+				seen[tld] = struct{}{}
+				tlds = append(tlds, tld)
+			}
+		}
+	}
+	sort.Strings(tlds)
+	return tlds
 }
 
 func fail(err error) {
@@ -53,7 +106,9 @@ func fail(err error) {
 	}
 }
 
-var pslTemplate = template.Must(template.New("").Parse(`package fasttld
+var pslTemplate = template.Must(template.New("").Parse(`//go:build !fasttld_nofallback
+
+package fasttld
 
 // Code generated by go generate; DO NOT EDIT.
 // This file was generated by robots at
@@ -62,3 +117,20 @@ var pslTemplate = template.Must(template.New("").Parse(`package fasttld
 // {{ .URL }}
 
 const hardcodedPSL string = ` + "`{{ .Content }}`\n"))
+
+var newGTLDsTemplate = template.Must(template.New("").Parse(`package fasttld
+
+// Code generated by go generate; DO NOT EDIT.
+// This file was generated by robots at
+// {{ .Timestamp }}
+// using data from
+// {{ .URL }}
+
+// newGTLDs are the new gTLDs (delegated from 2013 onwards) in the ICANN section
+// of the Public Suffix List, in both Unicode and punycode form.
+var newGTLDs = map[string]struct{}{
+{{- range .TLDs }}
+	"{{ . }}": {},
+{{- end }}
+}
+`))
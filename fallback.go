@@ -1,3 +1,5 @@
+//go:build !fasttld_nofallback
+
 package fasttld
 
 // Code generated by go generate; DO NOT EDIT.
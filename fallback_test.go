@@ -0,0 +1,48 @@
+//go:build !fasttld_nofallback
+
+package fasttld
+
+import "testing"
+
+func TestTrieConstructHardcoded(t *testing.T) {
+	tldTrie, err := trieConstruct(true, "")
+	if err != nil {
+		t.Errorf("error returned by trieConstruct should be nil")
+	}
+	rules := trieRules(tldTrie)
+	if len(rules.publicSuffixes) == 0 {
+		t.Errorf("len(rules.publicSuffixes) should be more than 0.")
+	}
+	if len(rules.privateSuffixes) == 0 {
+		t.Errorf("len(rules.privateSuffixes) should be more than 0.")
+	}
+}
+
+func TestNewHardcodedPSL(t *testing.T) {
+	f, err := newHardcodedPSL(nil, SuffixListParams{})
+	if err != nil {
+		t.Errorf("newHardcodedPSL error: %q", err)
+	}
+	if f.tldTrie.matches.Len() == 0 {
+		t.Errorf("tldTrie should not be empty")
+	}
+}
+
+func TestNewWithEmbeddedList(t *testing.T) {
+	for _, includePrivateSuffix := range []bool{false, true} {
+		f, err := NewWithEmbeddedList(SuffixListParams{CacheFilePath: "/this/path/does/not/exist", IncludePrivateSuffix: includePrivateSuffix})
+		if err != nil {
+			t.Fatalf("NewWithEmbeddedList error: %q", err)
+		}
+		if f.cacheFilePath != "" {
+			t.Errorf("cacheFilePath should be empty. Got %q.", f.cacheFilePath)
+		}
+		expected := "com"
+		if includePrivateSuffix {
+			expected = "blogspot.com"
+		}
+		if res, _ := f.Extract(URLParams{URL: "https://example.blogspot.com"}); res.Suffix != expected {
+			t.Errorf("Expected Suffix %q. Got %q.", expected, res.Suffix)
+		}
+	}
+}
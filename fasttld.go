@@ -7,11 +7,18 @@
 package fasttld
 
 import (
+	"context"
 	"errors"
+	"io"
 	"log"
+	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 
 	"github.com/spf13/afero"
 	"github.com/tidwall/hashmap"
@@ -23,13 +30,48 @@ const defaultPSLFileName string = "public_suffix_list.dat"
 const largestPortNumber int = 65535
 const pslMaxAgeHours float64 = 72
 
+// defaultCacheFolderPath returns the folder of the default Public Suffix List cache file,
+// with a trailing path separator. Tests replace it to avoid touching the system temporary folder.
+var defaultCacheFolderPath = func() string { return afero.GetTempDir(new(afero.OsFs), "") }
+
+// maxWalkSteps is the maximum number of trie nodes visited per extraction.
+// A valid hostname has at most 127 labels.
+const maxWalkSteps int = 127
+
+// ErrInvalidUTF8 is returned by Extract() if the URL host contains invalid UTF-8 byte sequences.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8 in hostname")
+
+// ErrInvalidLabel is returned by Extract() if a punycode label in the URL host decodes to a label containing a label separator.
+var ErrInvalidLabel = errors.New("punycode label decodes to label separator")
+
+// ErrWalkLimitExceeded is returned by Extract() if matching the URL host against the suffix list
+// visits more than 127 trie nodes, which is only possible with a pathologically deep suffix list.
+var ErrWalkLimitExceeded = errors.New("suffix list trie walk limit exceeded")
+
+// ErrEmptySuffixList is returned by New() and NewFromReader() if the Public Suffix List has no rules,
+// e.g. a misconfigured cache file which is empty or only has comments, unless SuffixListParams.AllowEmptyList = true.
+var ErrEmptySuffixList = errors.New("public suffix list has no rules")
+
 // FastTLD provides the Extract() function, to extract
 // URLs using tldTrie generated from the
 // Public Suffix List file at cacheFilePath.
+//
+// tldTrie is guarded by mu, as it can be modified by AddSuffix(), RemoveSuffix() and Update().
+// Update() builds a new trie before swapping it in, so Extract() never sees a partially built trie.
 type FastTLD struct {
 	cacheFilePath        string
+	updateMu             sync.Mutex // serializes Update(), which writes to the cache file
+	mu                   sync.RWMutex
 	tldTrie              *trie
 	includePrivateSuffix bool
+	httpClient           *http.Client
+	resultCache          *resultCache
+	walkLimitExceeded    atomic.Uint64
+}
+
+// WalkLimitExceededCount returns the number of extractions which failed with ErrWalkLimitExceeded.
+func (f *FastTLD) WalkLimitExceededCount() uint64 {
+	return f.walkLimitExceeded.Load()
 }
 
 // HostType indicates whether parsed URL
@@ -47,106 +89,559 @@ const (
 	IPv6
 )
 
+// DomainCase specifies the letter case of hostname components
+// (SubDomain, Domain, Suffix and RegisteredDomain) returned by Extract().
+type DomainCase int
+
+// LowerCase, UpperCase and AsInputCase specify the letter case of
+// hostname components returned by Extract().
+//
+// LowerCase maps ASCII letters to lower case, except in percent-escapes (e.g. "%A1").
+// UpperCase maps all letters to upper case.
+// AsInputCase leaves hostname components in the case they were given, except when converting
+// to punycode or Unicode, as IDNA mapping folds hostnames to lower case.
+//
+// Suffix matching is always ASCII case-insensitive, regardless of DomainCase.
+const (
+	LowerCase DomainCase = iota
+	UpperCase
+	AsInputCase
+)
+
+// Compatibility specifies how Extract() handles malformed URLs which URL parsers disagree on.
+type Compatibility int
+
+// Strict, WHATWG and Legacy specify the URL parser that Extract() is compatible with.
+//
+// Strict is the default behavior of Extract(). Leading and trailing whitespace and control characters
+// are trimmed, and backslashes are treated as slashes in the scheme delimiter (e.g. "http:\\example.com")
+// and at the end of the host, but kept as-is in Path.
+//
+// WHATWG follows the WHATWG URL Standard used by web browsers, differing from Strict in that
+// ASCII tabs and newlines anywhere in the URL are removed, backslashes in the Path of special schemes
+// (ftp, file, http, https, ws and wss) are replaced with "/", and file URLs with an empty host
+// (e.g. "file:///etc/passwd") are extracted with only Scheme and Path.
+//
+// Legacy follows IETF RFC 3986 parsers used by non-browser clients, differing from Strict in that
+// URLs with control characters, backslashes in the scheme delimiter or authority, or an empty
+// authority (e.g. "http:///example.com") are rejected, except file URLs with an empty host,
+// which are extracted as with WHATWG.
+const (
+	Strict Compatibility = iota
+	WHATWG
+	Legacy
+)
+
+// SuffixSection indicates the section of the Public Suffix List
+// that the extracted Suffix belongs to, if any.
+type SuffixSection int
+
+// NoSection, ICANNSection and PrivateSection indicate whether the extracted Suffix
+// belongs to the ICANN section, the PRIVATE section of the Public Suffix List,
+// or whether no Suffix was found.
+const (
+	NoSection SuffixSection = iota
+	ICANNSection
+	PrivateSection
+)
+
 // ExtractResult contains components extracted from URL.
+//
+// Its fields are grouped by URL component: scheme, authority, hostname and path,
+// followed by flags describing the URL and how it was extracted.
+//
+// IntentScheme is the scheme embedded in the fragment of Android intent URLs,
+// e.g. "https" for intent://example.com/path#Intent;scheme=https;end
+//
+// PackageName is the app identifier in the host position of android-app:// and ios-app://
+// deep links, e.g. "com.example.app" for android-app://com.example.app/https/example.com.
+// It is not matched against the Public Suffix List, and hostname components are left empty.
+//
+// Authority is the raw "userinfo@host:port" substring of the URL, as it appeared after
+// the scheme and surrounding whitespace were removed, e.g. for hashing the authority as a unit.
+// It is only set if URLParams.IncludeAuthority = true.
+//
+// SuffixSection is the section of the Public Suffix List containing the rule that matched Suffix.
+// If the rule is in both sections, SuffixSection is PrivateSection.
+//
+// Query and Fragment are the query and fragment of the URL, without their "?" and "#" delimiters.
+// They are only set if URLParams.SplitPath = true, otherwise they are part of Path. Fragment alone
+// is set if URLParams.SplitFragment = true. HasQuery and HasFragment are true if the split URL has
+// the "?" or "#" delimiter, including an empty query or fragment, e.g. "https://example.com/a?".
+//
+// IsBlob is true for blob URLs (e.g. blob:https://example.com/uuid), in which case
+// the other components are extracted from the origin embedded in the blob URL.
+//
+// WasFQDN is true if the hostname ended with a label separator denoting the DNS root (e.g. "example.com."),
+// which is stripped from the hostname components.
+//
+// IDNAApplied is true if converting the hostname to punycode changed it other than by ASCII case,
+// e.g. for internationalised hostnames like "例子.中国". It is only set for hostnames extracted with
+// URLParams.ConvertURLToPunyCode or URLParams.ConvertURLToUnicode.
+//
+// HadHomoglyphSeparators is true if the URL host contains lookalikes of "." that are not
+// label separators, e.g. U+2024 ONE DOT LEADER (see URLParams.MapHomoglyphSeparators).
+//
+// Degraded is true for best effort results of invalid URLs (see URLParams.BestEffort).
+// Degraded results are not canonical, and should only be used for purposes like logging.
 type ExtractResult struct {
-	Scheme, UserInfo, SubDomain, Domain, Suffix, RegisteredDomain, Port, Path string
-	HostType                                                                  HostType
+	Scheme, IntentScheme, PackageName string
+
+	UserInfo, Authority, Port string
+
+	SubDomain, Domain, Suffix, RegisteredDomain string
+	HostType                                    HostType
+	SuffixSection                               SuffixSection
+
+	Path, Query, Fragment string
+
+	// bool flags are grouped together to avoid padding
+	IsBlob                                                       bool
+	WasFQDN, IDNAApplied, HadHomoglyphSeparators, HasBidiControl bool
+	HasQuery, HasFragment                                        bool
+	Degraded                                                     bool
 }
 
 // SuffixListParams contains parameters for specifying path to Public Suffix List file and
 // whether to extract private suffixes (e.g. blogspot.com).
+//
+// If IncludePrivateSuffix = false, only the ICANN section of the Public Suffix List is stored.
+//
+// If SuffixListURL is set and CacheFilePath does not contain a valid Public Suffix List,
+// the Public Suffix List is downloaded from SuffixListURL to CacheFilePath
+// (or the default cache file path if CacheFilePath is empty).
+//
+// HTTPClient is used to download the Public Suffix List, including by Update().
+// If HTTPClient is nil, a client with a 60 second timeout is used.
+//
+// If CacheSize > 0, the results of Extract() for up to CacheSize of the most recently used
+// URLParams are cached in memory, trading memory for CPU on repetitive inputs. Cached results
+// are shared between callers, and must be treated as immutable. URLParams with a WildcardResolver
+// are not cached. The cache is cleared whenever the suffix list is modified.
+//
+// By default, a Public Suffix List without any rules (e.g. an empty file) is rejected with ErrEmptySuffixList.
+// If AllowEmptyList = true, it is accepted, and no suffixes are matched until rules are added with AddSuffix().
 type SuffixListParams struct {
 	CacheFilePath        string
 	IncludePrivateSuffix bool
+	SuffixListURL        string
+	HTTPClient           *http.Client
+	CacheSize            int
+	AllowEmptyList       bool
 }
 
 // URLParams specifies URL to extract components from.
 //
 // If IgnoreSubDomains = true, do not extract SubDomain.
 //
-// If ConvertURLToPunyCode = true, convert non-ASCII characters like 世界 to punycode.
+// If ConvertURLToPunyCode = true, convert non-ASCII characters like 世界 in the hostname to punycode.
+// UserInfo and Path are always returned as-is.
+//
+// If ConvertURLToUnicode = true, punycode labels like xn--rhqv96g in the hostname components are
+// returned in Unicode form. Suffixes are still matched against the punycode form of the hostname.
+// ConvertURLToUnicode and ConvertURLToPunyCode are mutually exclusive.
+//
+// If PreserveSeparators = true, internationalised label separators (e.g. "．") are kept in the
+// hostname when converting it to punycode, instead of being mapped to ".". This converts
+// each label separately and is therefore slower. Without punycode conversion, label separators
+// are always preserved.
+//
+// DomainCase specifies the letter case of extracted hostname components. Defaults to LowerCase.
+// Use AsInputCase to return the host in the exact case it was typed.
+//
+// If StrictBidi = true, reject hostnames with bidirectional control characters (e.g. U+202E)
+// or labels failing the IDNA Bidi Rule (IETF RFC 5893). Otherwise, hostnames with bidirectional
+// control characters are flagged with ExtractResult.HasBidiControl.
+//
+// If BestEffort = true and the URL is invalid, Extract() still returns its error, but the
+// ExtractResult contains hostname components extracted from the longest substring of the URL host
+// that looks like a hostname, and has Degraded = true.
+//
+// If WildcardResolver is not nil, it is called whenever a wildcard rule (e.g. *.ck) matches,
+// with base being the suffix under the wildcard (e.g. "ck") and label being the label
+// matched by the wildcard (e.g. "example"). If it returns false, label is not treated as
+// part of the Suffix. By default, all labels are accepted. WildcardResolver is called without
+// holding the suffix list lock, so it may call AddSuffix() or RemoveSuffix(), but their changes
+// only apply to later extractions.
+//
+// If RequireScheme = true, reject URLs without a scheme (e.g. "example.com:8080") instead of
+// treating them as starting with a hostname.
+//
+// If SortQueryParams = true, sort the query parameters in Path by key.
+//
+// By default, a leftmost "*" label (e.g. "*.example.com" in configuration files) is extracted
+// as a SubDomain label. If RejectWildcardHost = true, such hostnames are rejected.
+//
+// If MapHomoglyphSeparators = true, lookalikes of "." that are not label separators
+// (e.g. U+2024 ONE DOT LEADER) are mapped to "." before extraction. This is a security
+// feature for spotting disguised hostnames, and is not IETF RFC 3490 compliant.
+// Such hostnames are flagged with ExtractResult.HadHomoglyphSeparators regardless.
+//
+// If UnknownSuffixPlaceholder is not empty, it is returned as the Suffix of hostnames without
+// a matching Public Suffix List rule, instead of an empty string. The placeholder is cosmetic,
+// use ExtractResult.SuffixMatched() to check whether a Suffix was matched.
+//
+// If InternSuffixes = true, Suffix shares memory with the Public Suffix List rule it matches
+// instead of the URL, reducing memory use when holding many results. Suffixes matched by
+// wildcard rules, or not in lower case, are not interned.
+//
+// If SCPSyntax = true, URLs without a scheme in the scp-like shorthand "user@host:path"
+// (e.g. "git@github.com:user/repo.git") are extracted with the colon as the separator
+// between host and Path, if the part after the colon is not numeric. The colon is not part of Path.
+//
+// If LabelSeparators is not empty, each of its runes (e.g. "|") is mapped to "." in the hostname
+// before extraction, in addition to the default label separators. Letters, digits, whitespace and
+// characters delimiting other URL components (e.g. "/", ":", "@") are rejected.
+//
+// If IncludeAuthority = true, ExtractResult.Authority is set to the raw authority of the URL.
+//
+// By default, ports with non-ASCII digits are rejected. If NormalizePortDigits = true, Unicode
+// decimal digits in the port (e.g. Arabic-Indic digits like "٨٠٨٠") are mapped to ASCII digits instead.
+//
+// Ports which are not numbers from 0 to 65535 are always rejected. If ValidatePort = true,
+// port 0 and ports with a sign (e.g. "+80") are rejected too, so that Port is from 1 to 65535.
+//
+// If SplitPath = true, the query and fragment are split from Path into ExtractResult.Query and
+// ExtractResult.Fragment, and Path only contains the path. Percent-encoding is preserved.
+// If SplitFragment = true, only the fragment is split from Path, and the query is kept in Path.
+// The fragment starts at the first unescaped "#" after the URL authority, so "%23" does not start it.
+//
+// Compatibility specifies how malformed URLs are handled. Defaults to Strict.
+//
+// A single trailing label separator (e.g. "example.com.") denotes the DNS root, and is stripped before
+// matching. By default, further trailing label separators are stripped too. If StrictTrailingDot = true,
+// hosts with more than one trailing label separator (e.g. "example.com..") are rejected.
 type URLParams struct {
-	URL                  string
-	IgnoreSubDomains     bool
-	ConvertURLToPunyCode bool
+	URL                      string
+	IgnoreSubDomains         bool
+	ConvertURLToPunyCode     bool
+	ConvertURLToUnicode      bool
+	PreserveSeparators       bool
+	DomainCase               DomainCase
+	StrictBidi               bool
+	BestEffort               bool
+	WildcardResolver         func(base, label string) bool
+	RequireScheme            bool
+	SortQueryParams          bool
+	InternSuffixes           bool
+	RejectWildcardHost       bool
+	MapHomoglyphSeparators   bool
+	UnknownSuffixPlaceholder string
+	SCPSyntax                bool
+	LabelSeparators          string
+	IncludeAuthority         bool
+	NormalizePortDigits      bool
+	StrictTrailingDot        bool
+	ValidatePort             bool
+	SplitPath                bool
+	SplitFragment            bool
+	Compatibility            Compatibility
 }
 
 // trie is a node of the compressed trie
 // used to store Public Suffix List eTLDs.
 type trie struct {
 	matches hashmap.Map[string, *trie]
-	end     bool
+	// exceptions holds the exception rule nodes of matches, keyed by label without the "!" prefix
+	exceptions hashmap.Map[string, *trie]
+	end        bool
+	private    bool
+	icann      bool
+	// privateRule is true if a PRIVATE section rule ends at this node
+	privateRule bool
+	suffix      string // Public Suffix List rule ending at this node, if any
+	line        int    // Public Suffix List line number of the first rule ending at this node, if any
+}
+
+// section returns the Public Suffix List section of the eTLD ending at this node.
+func (t *trie) section() SuffixSection {
+	if t.private || t.privateRule {
+		return PrivateSection
+	}
+	return ICANNSection
 }
 
 // nestedDict stores a slice of keys in the trie, by traversing the trie using the keys as a "path",
 // creating new tries for keys that do not exist yet.
 //
 // If a new path overlaps an existing path, flag the previous path's trie node as end = true.
-func nestedDict(dic *trie, keys []string) {
+//
+// Nodes are flagged as private = true only if they are not part of any ICANN section path.
+// The last node is flagged as icann = true if the path is from the ICANN section,
+// and as privateRule = true if the path is from the PRIVATE section.
+//
+// Returns the last node.
+func nestedDict(dic *trie, keys []string, private bool) *trie {
 	for _, key := range keys {
 		if _, ok := dic.matches.Get(key); !ok {
 			// key doesn't exist; add new node
 			var m hashmap.Map[string, *trie]
-			dic.matches.Set(key, &trie{matches: m})
+			node := &trie{matches: m, private: private}
+			dic.matches.Set(key, node)
+			if strings.HasPrefix(key, "!") {
+				dic.exceptions.Set(key[1:], node)
+			}
 		}
 		dic, _ = dic.matches.Get(key)
+		if !private {
+			dic.private = false
+		}
 	}
 	// set last node to end = true
 	dic.end = true
+	if private {
+		dic.privateRule = true
+	} else {
+		dic.icann = true
+	}
+	return dic
 }
 
 // trieConstruct constructs a compressed trie to store Public Suffix List eTLDs split at "." in reverse-order.
 //
 // For example: "us.gov.pl" will be stored in the order {"pl", "gov", "us"}.
 func trieConstruct(includePrivateSuffix bool, cacheFilePath string) (*trie, error) {
-	var m hashmap.Map[string, *trie]
-	tldTrie := &trie{matches: m}
-
-	var suffixLists suffixes
-	var err error
+	var r io.Reader
 	if cacheFilePath != "" {
-		suffixLists, err = getPublicSuffixList(cacheFilePath)
+		file, err := os.Open(cacheFilePath)
+		if err != nil {
+			log.Println(err)
+			return NewTrieBuilder(includePrivateSuffix).tldTrie, err
+		}
+		defer file.Close()
+		r = file
 	} else {
-		suffixLists, err = getHardcodedPublicSuffixList()
+		contents, err := getHardcodedPSL()
+		if err != nil {
+			log.Println(err)
+			return NewTrieBuilder(includePrivateSuffix).tldTrie, err
+		}
+		r = strings.NewReader(contents)
 	}
 
+	tldTrie, err := readSuffixTrie(includePrivateSuffix, r)
 	if err != nil {
 		log.Println(err)
-		return tldTrie, err
+		return NewTrieBuilder(includePrivateSuffix).tldTrie, err
 	}
+	return tldTrie, nil
+}
 
-	var suffixList []string
-	if includePrivateSuffix {
-		suffixList = suffixLists.allSuffixes
-	} else {
-		suffixList = suffixLists.publicSuffixes
+// readSuffixTrie constructs a compressed trie from the Public Suffix List read from r,
+// which may be gzip compressed. All suffix list loaders parse the list with it.
+func readSuffixTrie(includePrivateSuffix bool, r io.Reader) (*trie, error) {
+	contents, err := readAll(r)
+	if err != nil {
+		return nil, err
 	}
+	return suffixTrie(includePrivateSuffix, string(contents)), nil
+}
+
+// suffixTrie constructs a compressed trie to store the rules of Public Suffix List file contents,
+// with their line numbers. Private suffixes are only stored if includePrivateSuffix = true.
+func suffixTrie(includePrivateSuffix bool, contents string) *trie {
+	builder := NewTrieBuilder(includePrivateSuffix)
+	for _, line := range strings.Split(contents, "\n") {
+		builder.Add(line)
+	}
+	return builder.trie()
+}
+
+// Extract components from a given `url`.
+//
+// Returns an error if the URL has an empty host, an invalid port, or an invalid host.
+// Partially extracted components are still returned with the error. Hostnames without
+// a matching Public Suffix List rule (e.g. "localhost") are valid; see ExtractResult.SuffixMatched().
+func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
+	if f.resultCache == nil || e.WildcardResolver != nil {
+		return f.extractWithBestEffort(e)
+	}
+	key := newResultCacheKey(e)
+	entry, generation := f.resultCache.get(key)
+	if entry != nil {
+		return entry.res, entry.err
+	}
+	urlParts, err := f.extractWithBestEffort(e)
+	f.resultCache.add(key, generation, urlParts, err)
+	return urlParts, err
+}
+
+// extractWithBestEffort extracts components from e.URL, falling back to extractBestEffort
+// if e.BestEffort = true and e.URL is invalid.
+func (f *FastTLD) extractWithBestEffort(e URLParams) (ExtractResult, error) {
+	urlParts, err := f.extract(e)
+	if err != nil && e.BestEffort {
+		return f.extractBestEffort(e, urlParts), err
+	}
+	return urlParts, err
+}
+
+// ExtractValue extracts components from url with default URLParams,
+// and returns an empty ExtractResult if url is invalid.
+//
+// ExtractValue does not allocate on the heap for valid lower case URLs without
+// percent-encoded characters if the returned ExtractResult does not escape.
+// Note that the string fields of ExtractResult may share memory with url.
+func (f *FastTLD) ExtractValue(url string) ExtractResult {
+	if res, err := f.Extract(URLParams{URL: url}); err == nil {
+		return res
+	}
+	return ExtractResult{}
+}
+
+// extractBestEffort returns urlParts from a failed extraction of e.URL, with its hostname components
+// replaced by those of the longest substring of the URL host that looks like a hostname.
+func (f *FastTLD) extractBestEffort(e URLParams, urlParts ExtractResult) ExtractResult {
+	urlParts.Degraded = true
 
-	for _, suffix := range suffixList {
-		sp := strings.Split(suffix, ".")
-		reverse(sp)
-		nestedDict(tldTrie, sp)
+	netloc := fastTrim(e.URL, whitespaceRuneSet, trimBoth)
+	if schemeEndIndex := getSchemeEndIndex(netloc); schemeEndIndex != -1 {
+		netloc = netloc[schemeEndIndex:]
+	}
+	if atIdx := indexLastByteBefore(netloc, '@', invalidUserInfoCharsSet); atIdx != -1 {
+		netloc = netloc[atIdx+1:]
+	}
+	if hostEndIdx := indexAnyASCII(netloc, endOfHostDelimitersSet); hostEndIdx != -1 {
+		netloc = netloc[0:hostEndIdx]
 	}
 
-	tldTrie.matches.Scan(func(key string, value *trie) bool {
-		if _, ok := value.matches.Get("*"); ok {
-			value.end = true
+	// find longest run of valid hostname characters
+	var host string
+	runStartIdx := -1
+	for idx, r := range netloc + " " {
+		if r != utf8.RuneError && !invalidHostNameCharsRuneSet.Exists(r) {
+			if runStartIdx == -1 {
+				runStartIdx = idx
+			}
+			continue
+		}
+		if runStartIdx != -1 {
+			if run := fastTrim(netloc[runStartIdx:idx], bestEffortTrimRuneSet, trimBoth); len(run) > len(host) {
+				host = run
+			}
+			runStartIdx = -1
 		}
-		return true
-	})
+	}
 
-	return tldTrie, nil
+	bestEffortParams := e
+	bestEffortParams.URL = host
+	if res, err := f.extract(bestEffortParams); err == nil {
+		urlParts.SubDomain, urlParts.Domain, urlParts.Suffix = res.SubDomain, res.Domain, res.Suffix
+		urlParts.RegisteredDomain, urlParts.HostType, urlParts.SuffixSection = res.RegisteredDomain, res.HostType, res.SuffixSection
+	} else {
+		urlParts.SubDomain, urlParts.Suffix, urlParts.RegisteredDomain = "", "", ""
+		urlParts.Domain = toLowerASCII(host)
+		urlParts.HostType, urlParts.SuffixSection = None, NoSection
+	}
+	return urlParts
 }
 
-// Extract components from a given `url`.
-func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
+// extract components from a given `url`.
+func (f *FastTLD) extract(e URLParams) (ExtractResult, error) {
 	urlParts := ExtractResult{}
 
+	if !validLabelSeparators(e.LabelSeparators) {
+		return urlParts, errors.New("invalid label separators")
+	}
+	if e.ConvertURLToPunyCode && e.ConvertURLToUnicode {
+		return urlParts, errors.New("ConvertURLToPunyCode and ConvertURLToUnicode are mutually exclusive")
+	}
+
+	if e.Compatibility == Legacy && indexAnyASCII(e.URL, controlCharsSet) != -1 {
+		// RFC 3986 parsers reject control characters instead of trimming them
+		return urlParts, errors.New("control characters in URL")
+	}
+
 	// Extract URL scheme
 	netloc := fastTrim(e.URL, whitespaceRuneSet, trimBoth)
+	if e.Compatibility == WHATWG && strings.ContainsAny(netloc, "\t\n\r") {
+		// WHATWG URL parsers remove all ASCII tabs and newlines
+		netloc = tabNewlineReplacer.Replace(netloc)
+	}
+	if hasBlobScheme(netloc) {
+		urlParts.IsBlob = true
+		netloc = netloc[len(blobScheme):]
+	}
 	if schemeEndIndex := getSchemeEndIndex(netloc); schemeEndIndex != -1 {
 		urlParts.Scheme = netloc[0:schemeEndIndex]
 		netloc = netloc[schemeEndIndex:]
+	} else if schemeEndIndex := getOpaqueSchemeEndIndex(netloc); schemeEndIndex != -1 {
+		// Opaque schemes have no "//", e.g. "mailto:user@example.com"
+		urlParts.Scheme = netloc[0:schemeEndIndex]
+		netloc = netloc[schemeEndIndex:]
+		if !opaqueSchemes[urlParts.schemeName()] {
+			// The rest of the URL has no host, e.g. "tel:+1-201-555-0123"
+			urlParts.Path = netloc
+			if e.SplitPath {
+				urlParts.Path, urlParts.Query, urlParts.Fragment, urlParts.HasQuery, urlParts.HasFragment = splitPath(urlParts.Path)
+			} else if e.SplitFragment {
+				urlParts.Path, urlParts.Fragment, urlParts.HasFragment = splitFragment(urlParts.Path)
+			}
+			return urlParts, nil
+		}
+	}
+	if e.Compatibility != Strict {
+		if e.Compatibility == Legacy && strings.ContainsRune(urlParts.Scheme, '\\') {
+			return urlParts, errors.New("backslash in URL authority")
+		}
+		// Slashes after the first two delimit an empty authority, e.g. "file:///etc/passwd"
+		if slashesStartIdx := strings.IndexByte(urlParts.Scheme, ':') + 1; len(urlParts.Scheme)-slashesStartIdx > 2 {
+			if urlParts.schemeName() == "file" {
+				urlParts.Path = urlParts.Scheme[slashesStartIdx+2:] + netloc
+				urlParts.Scheme = urlParts.Scheme[0 : slashesStartIdx+2]
+				if e.Compatibility == WHATWG {
+					urlParts.Path = normalizePathBackslashes(urlParts.Path)
+				}
+				if e.SplitPath {
+					urlParts.Path, urlParts.Query, urlParts.Fragment, urlParts.HasQuery, urlParts.HasFragment = splitPath(urlParts.Path)
+				} else if e.SplitFragment {
+					urlParts.Path, urlParts.Fragment, urlParts.HasFragment = splitFragment(urlParts.Path)
+				}
+				return urlParts, nil
+			}
+			if e.Compatibility == Legacy {
+				return urlParts, errors.New("empty host")
+			}
+		}
+		if e.Compatibility == Legacy {
+			authorityEndIdx := strings.IndexAny(netloc, "/?#")
+			if authorityEndIdx == -1 {
+				authorityEndIdx = len(netloc)
+			}
+			if strings.IndexByte(netloc[0:authorityEndIdx], '\\') != -1 {
+				return urlParts, errors.New("backslash in URL authority")
+			}
+		}
+	}
+	authority := netloc
+	if e.RequireScheme && len(urlParts.schemeName()) == 0 {
+		// Reject schemeless URLs instead of treating them as hostnames
+		return urlParts, errors.New("missing scheme")
+	}
+
+	// App deep links have an app package name instead of a hostname
+	if urlParts.SchemeIs("android-app", "ios-app") {
+		packageEndIdx := indexAnyASCII(netloc, endOfHostWithPortDelimitersSet)
+		if packageEndIdx == -1 {
+			packageEndIdx = len(netloc)
+		}
+		urlParts.PackageName = netloc[0:packageEndIdx]
+		if e.IncludeAuthority {
+			urlParts.Authority = urlParts.PackageName
+		}
+		urlParts.Path = netloc[packageEndIdx:]
+		if e.SortQueryParams {
+			urlParts.Path = sortQueryParams(urlParts.Path)
+		}
+		if e.SplitPath {
+			urlParts.Path, urlParts.Query, urlParts.Fragment, urlParts.HasQuery, urlParts.HasFragment = splitPath(urlParts.Path)
+		} else if e.SplitFragment {
+			urlParts.Path, urlParts.Fragment, urlParts.HasFragment = splitFragment(urlParts.Path)
+		}
+		if len(urlParts.PackageName) == 0 {
+			return urlParts, errors.New("empty package name")
+		}
+		return urlParts, nil
 	}
 
 	// Extract URL userinfo
@@ -156,6 +651,9 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 	}
 
 	// Find square brackets (if any) and host end index
+	//
+	// Scanning bytes is safe for internationalised label separators (e.g. "｡"),
+	// as none of their UTF-8 bytes are ASCII delimiters.
 	openingSquareBracketIdx := -1
 	closingSquareBracketIdx := -1
 	hostEndIdx := -1
@@ -206,7 +704,7 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 
 	// Check for IPv6 address
 	if closingSquareBracketIdx > openingSquareBracketIdx {
-		if !isIPv6(netloc[1:closingSquareBracketIdx]) {
+		if !isIPv6WithZone(netloc[1:closingSquareBracketIdx]) {
 			// Have square brackets but invalid IPv6 address => Domain is invalid
 			return urlParts, errors.New("invalid IPv6 address")
 		}
@@ -223,7 +721,7 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 		urlParts.RegisteredDomain = netloc[1:closingSquareBracketIdx]
 	}
 
-	var afterHost string
+	var afterHost, rawPort string
 	// Separate URL host from subcomponents thereafter
 	if hostEndIdx != -1 {
 		afterHost = netloc[hostEndIdx:]
@@ -234,14 +732,23 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 	if len(afterHost) != 0 {
 		pathStartIndex := indexAnyASCII(afterHost, endOfHostWithPortDelimitersSet)
 		if afterHost[0] == ':' {
-			var maybePort string
 			if pathStartIndex == -1 {
-				maybePort = afterHost[1:]
+				rawPort = afterHost[1:]
 			} else {
-				maybePort = afterHost[1:pathStartIndex]
+				rawPort = afterHost[1:pathStartIndex]
+			}
+			maybePort := rawPort
+			if e.NormalizePortDigits {
+				maybePort = normalizeDigits(maybePort)
 			}
 			if port, err := strconv.Atoi(maybePort); err == nil && 0 <= port && port <= largestPortNumber {
+				if e.ValidatePort && (port == 0 || !isASCIIDigits(maybePort)) {
+					return urlParts, errors.New("invalid port")
+				}
 				urlParts.Port = maybePort
+			} else if err != nil && e.SCPSyntax && len(urlParts.Scheme) == 0 && len(urlParts.UserInfo) != 0 {
+				// scp-like shorthand "user@host:path" ; colon separates host from Path
+				pathStartIndex = 1
 			} else {
 				return urlParts, errors.New("invalid port")
 			}
@@ -251,49 +758,144 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 			// See https://stackoverflow.com/questions/47543432/what-do-we-call-the-combined-path-query-and-fragment-in-a-uri
 			// For simplicity, we shall call this the "Path".
 			urlParts.Path = afterHost[pathStartIndex:]
+			if e.Compatibility == WHATWG && urlParts.SchemeIs(specialSchemes...) {
+				urlParts.Path = normalizePathBackslashes(urlParts.Path)
+			}
+			if e.SortQueryParams {
+				urlParts.Path = sortQueryParams(urlParts.Path)
+			}
+			if urlParts.SchemeIs("intent") {
+				urlParts.IntentScheme = intentScheme(urlParts.Path)
+			}
+			if e.SplitPath {
+				urlParts.Path, urlParts.Query, urlParts.Fragment, urlParts.HasQuery, urlParts.HasFragment = splitPath(urlParts.Path)
+			} else if e.SplitFragment {
+				urlParts.Path, urlParts.Fragment, urlParts.HasFragment = splitFragment(urlParts.Path)
+			}
+		}
+	}
+
+	if e.IncludeAuthority {
+		authorityEndIdx := len(authority) - len(afterHost)
+		if len(urlParts.Port) != 0 {
+			authorityEndIdx += len(":") + len(rawPort)
 		}
+		urlParts.Authority = authority[0:authorityEndIdx]
 	}
 
 	if urlParts.HostType == IPv6 {
 		return urlParts, nil
 	}
 
+	// Reject invalid UTF-8 byte sequences (e.g. lone continuation bytes, overlong encodings)
+	// instead of matching them as utf8.RuneError
+	if !utf8.ValidString(netloc) {
+		return urlParts, ErrInvalidUTF8
+	}
+
+	if len(e.LabelSeparators) != 0 {
+		netloc = mapLabelSeparators(netloc, e.LabelSeparators)
+	}
+
+	// Flag lookalikes of "." which can be used to disguise hostnames
+	if indexAny(netloc, homoglyphSeparatorsRuneSet) != -1 {
+		urlParts.HadHomoglyphSeparators = true
+		if e.MapHomoglyphSeparators {
+			netloc = homoglyphSeparatorReplacer.Replace(netloc)
+		}
+	}
+
+	// Flag bidirectional control characters, which can be used to disguise hostnames
+	if indexAny(netloc, bidiControlCharsRuneSet) != -1 {
+		urlParts.HasBidiControl = true
+		if e.StrictBidi {
+			return urlParts, errors.New("bidirectional control characters in hostname")
+		}
+	}
+
 	// decode all percentage encoded characters, if any
 	unescapedNetloc, err := url.QueryUnescape(netloc)
 	if err != nil {
 		return urlParts, err
 	}
 
-	if e.ConvertURLToPunyCode {
-		netloc = formatAsPunycode(unescapedNetloc)
-	} else if _, err := idna.ToUnicode(unescapedNetloc); err != nil {
+	var idnaApplied bool
+	if e.ConvertURLToPunyCode || e.ConvertURLToUnicode {
+		// Suffixes are matched against the punycode form of the hostname,
+		// ConvertURLToUnicode only decodes the extracted hostname components.
+		idnaInput := unescapedNetloc
+
+		// "*" is not a valid IDNA label; convert only the labels after it
+		var wildcardLabel string
+		if n := wildcardLabelLen(unescapedNetloc); n != 0 && !e.RejectWildcardHost {
+			wildcardLabel, unescapedNetloc = unescapedNetloc[0:n], unescapedNetloc[n:]
+		}
+		if e.PreserveSeparators {
+			netloc = formatLabelsAsPunycode(unescapedNetloc)
+		} else {
+			netloc = formatAsPunycode(unescapedNetloc)
+			if len(wildcardLabel) != 0 {
+				wildcardLabel = "*."
+			}
+		}
+		if len(netloc) != 0 {
+			netloc = wildcardLabel + netloc
+			idnaApplied = netloc != toLowerASCII(idnaInput)
+		}
+	} else if unicodeNetloc, err := idna.ToUnicode(unescapedNetloc); err != nil {
 		// host is invalid if host cannot be converted to Unicode
 		//
 		// skip if host already converted to punycode
 		log.Println(strings.SplitAfterN(err.Error(), "idna: invalid label", 2)[0])
 		return urlParts, err
+	} else if countAny(unicodeNetloc, labelSeparatorsRuneSet) != countAny(unescapedNetloc, labelSeparatorsRuneSet) {
+		// a single label must not decode to multiple labels
+		return urlParts, ErrInvalidLabel
+	}
+
+	if e.StrictBidi {
+		// reject mixed-direction labels
+		if _, err := idnaBidiRule.ToASCII(unescapedNetloc); err != nil {
+			return urlParts, err
+		}
+	}
+
+	// Match suffixes against a lowercase copy of the host.
+	// Hostname components are sliced from netloc only if their case is to be preserved.
+	host := toLowerHost(netloc)
+	if e.DomainCase != AsInputCase {
+		netloc = host
 	}
 
 	// Check for eTLD Suffix
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 	node := f.tldTrie
 
 	var (
 		hasSuffix      bool
 		hasLabels      bool
 		end            bool
+		walkSteps      int
 		previousSepIdx int
+		section        SuffixSection
+		ruleSuffix     string // suffix of the longest matching rule
 	)
 	sepIdx, suffixStartIdx, suffixEndIdx := len(netloc), len(netloc), len(netloc)
+	ruleSepIdx := sepIdx // sepIdx of the longest matching rule
 
 	for !end {
 		var label string
 		previousSepIdx = sepIdx
 		sepIdx = lastIndexAny(netloc[0:sepIdx], labelSeparatorsRuneSet)
 		if sepIdx != -1 {
-			label = netloc[sepIdx+sepSize(netloc[sepIdx]) : previousSepIdx]
+			label = host[sepIdx+sepSize(netloc[sepIdx]) : previousSepIdx]
 			if len(label) == 0 {
 				// allow consecutive label separators if suffix not found yet
 				if !hasLabels {
+					if e.StrictTrailingDot && suffixEndIdx != len(netloc) {
+						return urlParts, errors.New("multiple trailing label separators")
+					}
 					suffixEndIdx = sepIdx
 					continue
 				}
@@ -302,15 +904,22 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 			}
 			hasLabels = true
 		} else {
-			label = netloc[0:previousSepIdx]
+			label = host[0:previousSepIdx]
 			end = true
 		}
 
-		if _, ok := node.matches.Get("*"); ok {
+		if wildcard, ok := node.matches.Get("*"); ok {
+			// the suffix list may be modified while WildcardResolver runs,
+			// so read both outcomes from the trie before calling it
+			hasSuffix, ruleSepIdx, ruleSuffix, section = true, previousSepIdx, node.suffix, node.section()
 			// check if label falls under any wildcard exception rule
 			// e.g. !www.ck
-			if _, ok := node.matches.Get("!" + label); ok {
-				sepIdx = previousSepIdx
+			if _, ok := node.exceptions.Get(label); !ok {
+				wildcardSuffix, wildcardSection := wildcard.suffix, wildcard.section()
+				// unless label is rejected by caller
+				if e.WildcardResolver == nil || f.resolveWildcard(e.WildcardResolver, wildcardBase(host, previousSepIdx, suffixEndIdx), label) {
+					ruleSepIdx, ruleSuffix, section = sepIdx, wildcardSuffix, wildcardSection
+				}
 			}
 			break
 		}
@@ -318,11 +927,16 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 		// check if label is part of an eTLD
 		label, _ = url.QueryUnescape(label)
 		if val, ok := node.matches.Get(label); ok {
-			suffixStartIdx = sepIdx
-			if !hasSuffix && val.end {
-				// index of end of suffix without trailing label separators
-				suffixEndIdx = previousSepIdx
-				hasSuffix = true
+			if walkSteps++; walkSteps > maxWalkSteps {
+				f.walkLimitExceeded.Add(1)
+				return urlParts, ErrWalkLimitExceeded
+			}
+			if val.end {
+				// suffixEndIdx already excludes trailing label separators,
+				// even if the top level domain itself is not an eTLD (e.g. "corp.internal")
+				hasSuffix, ruleSepIdx, ruleSuffix = true, sepIdx, val.suffix
+				suffixStartIdx = sepIdx
+				section = val.section()
 			}
 			node = val
 			if val.matches.Len() == 0 {
@@ -337,6 +951,12 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 		}
 	}
 
+	if hasSuffix {
+		// labels matched beyond the longest rule are not part of the Suffix,
+		// e.g. "amazonaws" in "foo.amazonaws.com" if only "com" is a rule
+		sepIdx = ruleSepIdx
+	}
+
 	// Check for IPv4 address
 	// Minimum possible length: len("0.0.0.0") -> 7
 	// Ensure first rune is numeric before expensive isIPv4()
@@ -351,22 +971,33 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 		sepIdx, suffixStartIdx = len(netloc), len(netloc)
 	}
 
+	// Allow a leftmost "*" label (e.g. "*.example.com") as a SubDomain label
+	var wildcardLen int
+	if !e.RejectWildcardHost {
+		wildcardLen = wildcardLabelLen(netloc)
+	}
+
 	// Reject if invalidHostNameChars or consecutive label separators
 	// appears before Suffix
 	if hasSuffix {
-		if hasInvalidChars(netloc[0:suffixStartIdx]) {
+		if wildcardLen > suffixStartIdx || hasInvalidChars(netloc[wildcardLen:suffixStartIdx]) {
 			return urlParts, errors.New("invalid characters in hostname")
 		}
 	} else {
-		if hasInvalidChars(netloc[0:previousSepIdx]) {
+		if wildcardLen > previousSepIdx || hasInvalidChars(netloc[wildcardLen:previousSepIdx]) {
 			return urlParts, errors.New("invalid characters in hostname")
 		}
 	}
 
 	var domainStartSepIdx int
 	if hasSuffix {
+		urlParts.SuffixSection = section
 		if sepIdx < len(netloc) { // If there is a Domain
 			urlParts.Suffix = netloc[sepIdx+sepSize(netloc[sepIdx]) : suffixEndIdx]
+			if e.InternSuffixes && urlParts.Suffix == ruleSuffix {
+				// share memory with the Public Suffix List rule instead of netloc
+				urlParts.Suffix = ruleSuffix
+			}
 			domainStartSepIdx = lastIndexAny(netloc[0:sepIdx], labelSeparatorsRuneSet)
 			if domainStartSepIdx != -1 { // If there is a SubDomain
 				domainStartIdx := domainStartSepIdx + sepSize(netloc[domainStartSepIdx])
@@ -395,19 +1026,82 @@ func (f *FastTLD) Extract(e URLParams) (ExtractResult, error) {
 	if len(urlParts.Domain) == 0 {
 		return urlParts, errors.New("empty domain")
 	}
+	if wildcardLen != 0 && urlParts.Domain == "*" {
+		// "*" is only allowed as a SubDomain label
+		return urlParts, errors.New("invalid characters in hostname")
+	}
+	if e.ConvertURLToUnicode {
+		urlParts.SubDomain = formatLabelsAsUnicode(urlParts.SubDomain)
+		urlParts.Domain = formatLabelsAsUnicode(urlParts.Domain)
+		urlParts.Suffix = formatLabelsAsUnicode(urlParts.Suffix)
+		urlParts.RegisteredDomain = formatLabelsAsUnicode(urlParts.RegisteredDomain)
+	}
+	if e.DomainCase == UpperCase {
+		urlParts.SubDomain = strings.ToUpper(urlParts.SubDomain)
+		urlParts.Domain = strings.ToUpper(urlParts.Domain)
+		urlParts.Suffix = strings.ToUpper(urlParts.Suffix)
+		urlParts.RegisteredDomain = strings.ToUpper(urlParts.RegisteredDomain)
+	}
+	if !hasSuffix && len(e.UnknownSuffixPlaceholder) != 0 {
+		urlParts.Suffix = e.UnknownSuffixPlaceholder
+	}
 	urlParts.HostType = HostName
+	urlParts.WasFQDN = suffixEndIdx != len(netloc)
+	urlParts.IDNAApplied = idnaApplied
 	return urlParts, nil
 }
 
+// wildcardBase returns the Suffix matched before a wildcard rule,
+// given the index of the label separator before it and the end index of the Suffix.
+func wildcardBase(host string, sepIdx int, suffixEndIdx int) string {
+	if sepIdx >= suffixEndIdx {
+		return ""
+	}
+	return host[sepIdx+sepSize(host[sepIdx]) : suffixEndIdx]
+}
+
+// resolveWildcard calls resolver with base and label while f.mu is released, so that resolver
+// may modify the suffix list, e.g. with AddSuffix(). f.mu must be read locked by the caller,
+// and is read locked again when resolver returns or panics. Trie nodes read before calling
+// resolveWildcard may have been modified when it returns.
+func (f *FastTLD) resolveWildcard(resolver func(base, label string) bool, base, label string) bool {
+	f.mu.RUnlock()
+	defer f.mu.RLock()
+	return resolver(base, label)
+}
+
 // New creates a new *FastTLD using data from a Public Suffix List file.
 func New(n SuffixListParams) (*FastTLD, error) {
-	extractor := &FastTLD{cacheFilePath: n.CacheFilePath, tldTrie: &trie{}, includePrivateSuffix: n.IncludePrivateSuffix}
+	return NewContext(context.Background(), n)
+}
+
+// NewContext is like New, but aborts any download of the Public Suffix List if ctx is done.
+func NewContext(ctx context.Context, n SuffixListParams) (*FastTLD, error) {
+	extractor := &FastTLD{cacheFilePath: n.CacheFilePath, tldTrie: &trie{}, includePrivateSuffix: n.IncludePrivateSuffix,
+		httpClient: n.HTTPClient, resultCache: newResultCache(n.CacheSize)}
+	// If cacheFilePath is unreachable, download Public Suffix List from SuffixListURL if any
+	if isValid, _ := checkCacheFile(extractor.cacheFilePath); !isValid && n.SuffixListURL != "" {
+		cacheFilePath := extractor.cacheFilePath
+		if cacheFilePath == "" {
+			cacheFilePath = defaultCacheFolderPath() + defaultPSLFileName
+		}
+		if err := downloadToFile(ctx, n.HTTPClient, cacheFilePath, n.SuffixListURL); err != nil {
+			log.Println(err)
+		} else {
+			extractor.cacheFilePath = cacheFilePath
+		}
+	}
+	// A cache file without any rules, e.g. an empty file, is misconfigured and is not replaced by a fallback
+	emptyCacheFile := n.CacheFilePath != "" && hasNoRules(n.IncludePrivateSuffix, n.CacheFilePath)
+	if emptyCacheFile && !n.AllowEmptyList {
+		return nil, ErrEmptySuffixList
+	}
 	// If cacheFilePath is unreachable, use temporary folder
-	if isValid, _ := checkCacheFile(extractor.cacheFilePath); !isValid {
+	if isValid, _ := checkCacheFile(extractor.cacheFilePath); !isValid && !emptyCacheFile {
 		filesystem := new(afero.OsFs)
-		defaultCacheFolderPath := afero.GetTempDir(filesystem, "")
-		defaultCacheFilePath := defaultCacheFolderPath + defaultPSLFileName
-		defaultCacheFolder, err := filesystem.Open(defaultCacheFolderPath)
+		cacheFolderPath := defaultCacheFolderPath()
+		defaultCacheFilePath := cacheFolderPath + defaultPSLFileName
+		defaultCacheFolder, err := filesystem.Open(cacheFolderPath)
 		if err != nil {
 			// temporary folder not accessible, fallback to hardcoded Public Suffix list
 			return newHardcodedPSL(err, n)
@@ -417,7 +1111,7 @@ func New(n SuffixListParams) (*FastTLD, error) {
 		isValid, lastModifiedHours := checkCacheFile(extractor.cacheFilePath)
 		if !isValid || lastModifiedHours > pslMaxAgeHours {
 			// update Public Suffix list cache if it is outdated
-			if updateErr := extractor.Update(); updateErr != nil {
+			if updateErr := extractor.UpdateContext(ctx); updateErr != nil {
 				// update failed, fallback to hardcoded Public Suffix list
 				return newHardcodedPSL(err, n)
 			}
@@ -429,6 +1123,9 @@ func New(n SuffixListParams) (*FastTLD, error) {
 	if err != nil {
 		return newHardcodedPSL(err, n)
 	}
+	if tldTrie.matches.Len() == 0 && !n.AllowEmptyList {
+		return nil, ErrEmptySuffixList
+	}
 	extractor.tldTrie = tldTrie
 	return extractor, err
 }
@@ -0,0 +1,350 @@
+package fasttld
+
+import (
+	"errors"
+	"strings"
+)
+
+// OrganizationalDomain returns the DMARC Organizational Domain of host, as defined in
+// IETF RFC 7489 Section 3.2. This is the longest ICANN section public suffix of host
+// plus one label. PRIVATE section suffixes are always ignored.
+//
+// If no public suffix matches host, its top level domain is used as the public suffix.
+// host is returned in lower case, with internationalised label separators mapped to ".".
+func (f *FastTLD) OrganizationalDomain(host string) (string, error) {
+	host = strings.ToLower(labelSeparatorReplacer.Replace(host))
+	host = strings.TrimSuffix(host, ".")
+	if len(host) == 0 {
+		return "", errors.New("empty host")
+	}
+	if isIPv4(host) || isIPv6(host) {
+		return "", errors.New("IP address has no organizational domain")
+	}
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if len(label) == 0 {
+			return "", errors.New("invalid consecutive label separators on left-hand side of a label")
+		}
+	}
+
+	suffixLabelCount := f.icannSuffixLabelCount(labels)
+	if suffixLabelCount >= len(labels) {
+		return "", errors.New("host is a public suffix")
+	}
+	return strings.Join(labels[len(labels)-suffixLabelCount-1:], "."), nil
+}
+
+// icannSuffixLabelCount returns the number of labels in the longest ICANN section
+// public suffix matching labels. Returns 1 if no rule matches, as per the implicit "*" rule.
+func (f *FastTLD) icannSuffixLabelCount(labels []string) int {
+	suffixLabelCount := 1
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node := f.tldTrie
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := labels[i]
+		if wildcard, ok := node.matches.Get("*"); ok && !wildcard.private {
+			// check if label falls under any wildcard exception rule
+			// e.g. !www.ck
+			if exception, ok := node.exceptions.Get(label); ok && !exception.private {
+				suffixLabelCount = len(labels) - i - 1
+			} else {
+				suffixLabelCount = len(labels) - i
+			}
+			break
+		}
+		val, ok := node.matches.Get(label)
+		if !ok || val.private {
+			break
+		}
+		if val.icann {
+			suffixLabelCount = len(labels) - i
+		}
+		node = val
+	}
+	return suffixLabelCount
+}
+
+// PublicSuffix returns the public suffix of hostname host, and whether it is from the ICANN
+// section of the Public Suffix List, e.g. ("co.uk", true) for "www.example.co.uk".
+// Wildcard and exception rules are applied as in Extract, without parsing host as a URL.
+//
+// host may have a trailing dot. The suffix is returned in lower case, with internationalised
+// label separators mapped to ".". Returns an empty suffix if no rule matches host.
+func (f *FastTLD) PublicSuffix(host string) (suffix string, isICANN bool) {
+	host = strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(host)), ".")
+	labels := strings.Split(host, ".")
+
+	var hasSuffix bool
+	labelsLen := -1 // length of matched labels, excluding the label separator before them
+	suffixLen := -1 // length of suffix of the longest matching rule
+	section := NoSection
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node := f.tldTrie
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := labels[i]
+		if wildcard, ok := node.matches.Get("*"); ok {
+			// check if label falls under any wildcard exception rule
+			// e.g. !www.ck
+			if _, ok := node.exceptions.Get(label); ok {
+				hasSuffix, suffixLen, section = true, labelsLen, node.section()
+			} else {
+				hasSuffix, suffixLen, section = true, labelsLen+len(label)+1, wildcard.section()
+			}
+			break
+		}
+		val, ok := node.matches.Get(label)
+		if !ok {
+			break
+		}
+		labelsLen += len(label) + 1
+		if val.end {
+			hasSuffix, suffixLen, section = true, labelsLen, val.section()
+		}
+		node = val
+	}
+	if !hasSuffix {
+		return "", false
+	}
+	return host[len(host)-suffixLen:], section == ICANNSection
+}
+
+// IsPublicSuffix returns true if hostname host is itself a public suffix, e.g. "co.uk" or "foo.ck"
+// (because of *.ck), but not "example.co.uk" or "www.ck" (because of !www.ck). Suffixes from the
+// PRIVATE section of the Public Suffix List (e.g. "blogspot.com") only match if the FastTLD
+// includes private suffixes. host may have a trailing dot.
+func (f *FastTLD) IsPublicSuffix(host string) bool {
+	suffix, _ := f.PublicSuffix(host)
+	return len(suffix) != 0 && suffix == strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(host)), ".")
+}
+
+// IsPrivateSuffix returns true if hostname host is itself a public suffix from the PRIVATE section
+// of the Public Suffix List, e.g. "blogspot.com". Always returns false if the FastTLD does not include
+// private suffixes. host may have a trailing dot.
+func (f *FastTLD) IsPrivateSuffix(host string) bool {
+	suffix, isICANN := f.PublicSuffix(host)
+	return !isICANN && len(suffix) != 0 && suffix == strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(host)), ".")
+}
+
+// LongestSuffix returns the longest public suffix matching the end of host, even if the rest of host
+// is empty or malformed, e.g. ("co.uk", true) for both "co.uk" and "garbage!.co.uk". This is useful
+// for classifying partial or truncated hosts, which Extract rejects if they have no valid Domain.
+//
+// host may have a trailing dot. The suffix is returned in lower case, with internationalised
+// label separators mapped to ".". Returns ok = false if no rule matches host.
+func (f *FastTLD) LongestSuffix(host string) (suffix string, ok bool) {
+	host = strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(host)), ".")
+	suffixStartIdx := f.longestSuffixStartIdx(host)
+	if suffixStartIdx == -1 {
+		return "", false
+	}
+	return host[suffixStartIdx:], true
+}
+
+// RegisteredDomain returns the registered domain (eTLD+1) of hostname host, e.g. "example.co.uk"
+// for "www.example.co.uk", without parsing host as a URL. Wildcard and exception rules are applied
+// as in Extract.
+//
+// host may have a trailing dot. The registered domain is returned in lower case, with internationalised
+// label separators mapped to ".". Returns an empty string if host is not a valid hostname (e.g. it has
+// a scheme or port), is a public suffix, or does not match any rule.
+func (f *FastTLD) RegisteredDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(host)), ".")
+	if hasInvalidChars(host) {
+		return ""
+	}
+	suffixStartIdx := f.longestSuffixStartIdx(host)
+	if suffixStartIdx <= 0 {
+		// no suffix, or host is a suffix
+		return ""
+	}
+	return host[strings.LastIndexByte(host[0:suffixStartIdx-1], '.')+1:]
+}
+
+// MatchSuffix returns the start index of the longest public suffix matching the end of host,
+// without allocating, so that callers on hot paths can slice host themselves,
+// e.g. host[suffixStart:] is the suffix. isWildcard is true if the suffix was matched by a wildcard
+// rule (e.g. *.ck), and isException is true if a wildcard exception rule (e.g. !www.ck) applied.
+//
+// host must be in lower case with "." as label separators, and may have a trailing dot, which is
+// excluded from the suffix. Returns ok = false if no rule matches host.
+func (f *FastTLD) MatchSuffix(host string) (suffixStart int, isWildcard, isException, ok bool) {
+	suffixStart, isWildcard, isException = f.matchSuffix(strings.TrimSuffix(host, "."))
+	if suffixStart == -1 {
+		return 0, false, false, false
+	}
+	return suffixStart, isWildcard, isException, true
+}
+
+// longestSuffixStartIdx returns the index of the longest public suffix matching the end of host,
+// which must be in lower case with "." as label separators. Returns -1 if no rule matches host.
+func (f *FastTLD) longestSuffixStartIdx(host string) int {
+	suffixStartIdx, _, _ := f.matchSuffix(host)
+	return suffixStartIdx
+}
+
+// matchSuffix is like longestSuffixStartIdx, but also reports whether a wildcard or wildcard exception rule applied.
+func (f *FastTLD) matchSuffix(host string) (suffixStartIdx int, isWildcard, isException bool) {
+	suffixStartIdx = -1
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node := f.tldTrie
+	for labelEndIdx := len(host); labelEndIdx >= 0; {
+		labelStartIdx := strings.LastIndexByte(host[0:labelEndIdx], '.') + 1
+		label := host[labelStartIdx:labelEndIdx]
+		if _, ok := node.matches.Get("*"); ok {
+			// check if label falls under any wildcard exception rule
+			// e.g. !www.ck
+			if _, ok := node.exceptions.Get(label); ok {
+				isException = true
+			} else if len(label) != 0 {
+				suffixStartIdx = labelStartIdx
+				isWildcard = true
+			}
+			break
+		}
+		val, ok := node.matches.Get(label)
+		if !ok {
+			break
+		}
+		if val.end {
+			suffixStartIdx = labelStartIdx
+		}
+		node = val
+		labelEndIdx = labelStartIdx - 1
+	}
+	return suffixStartIdx, isWildcard, isException
+}
+
+// SettableCookieDomains returns the domains that host may set cookies for, as per IETF RFC 6265,
+// from host itself down to its registered domain. Public suffixes are excluded, unless host is
+// itself a public suffix.
+//
+// For IP addresses, only the IP address itself is returned.
+// Returns nil if host cannot be extracted.
+func (f *FastTLD) SettableCookieDomains(host string) []string {
+	res, err := f.Extract(URLParams{URL: host})
+	if err != nil {
+		if res.HostType == None && len(res.Suffix) != 0 {
+			// host is a public suffix
+			return []string{labelSeparatorReplacer.Replace(res.Suffix)}
+		}
+		return nil
+	}
+	host = res.Host()
+	registeredDomain := labelSeparatorReplacer.Replace(res.RegisteredDomain)
+	if res.HostType != HostName || len(registeredDomain) == 0 {
+		return []string{host}
+	}
+	domains := []string{host}
+	for host != registeredDomain {
+		host = host[strings.IndexByte(host, '.')+1:]
+		domains = append(domains, host)
+	}
+	return domains
+}
+
+// SiteForCookies returns the schemeful site of url used for SameSite cookie checks,
+// i.e. its scheme and registered domain, e.g. "https://example.co.uk".
+//
+// If url has no registered domain (e.g. IP addresses), its host is used instead.
+// Returns an empty string for opaque origins, i.e. if url is invalid or its scheme
+// is not one of http, https, ws or wss.
+func (f *FastTLD) SiteForCookies(url string) string {
+	res, err := f.Extract(URLParams{URL: url})
+	if err != nil || !res.SchemeIs("http", "https", "ws", "wss") {
+		return ""
+	}
+	site := res.Host()
+	if len(res.RegisteredDomain) != 0 && res.HostType == HostName {
+		site = labelSeparatorReplacer.Replace(res.RegisteredDomain)
+	}
+	return res.schemeName() + "://" + normalizeHost(site, res.HostType)
+}
+
+// CanonicalRegistrableDomain returns the registered domain of url in lower case punycode,
+// with internationalised label separators mapped to ".", e.g. "example.com" for
+// "www.example.com.", "example.com" and "WWW.EXAMPLE.COM". SubDomains like "www" and
+// trailing dots are never part of the registered domain.
+//
+// Returns an empty string if url is invalid or has no registered domain.
+func (f *FastTLD) CanonicalRegistrableDomain(url string) string {
+	res, err := f.Extract(URLParams{URL: url})
+	if err != nil || res.HostType != HostName || len(res.RegisteredDomain) == 0 {
+		return ""
+	}
+	return normalizeHost(labelSeparatorReplacer.Replace(res.RegisteredDomain), HostName)
+}
+
+// RDAPQueryDomain returns the registered domain of url in punycode (ACE) form,
+// as required for WHOIS and RDAP domain queries.
+//
+// Returns an error if url is invalid, is an IP address, or has no registered domain
+// (e.g. if url is a public suffix).
+func (f *FastTLD) RDAPQueryDomain(url string) (string, error) {
+	res, err := f.Extract(URLParams{URL: url, ConvertURLToPunyCode: true})
+	if err != nil {
+		return "", err
+	}
+	if res.IsIPAddress() {
+		return "", errors.New("IP address has no registered domain")
+	}
+	if len(res.RegisteredDomain) == 0 {
+		return "", errors.New("host has no registered domain")
+	}
+	return res.RegisteredDomain, nil
+}
+
+// IsHSTSPreloadable reports whether the host of url is eligible for HSTS preloading, i.e. it is
+// a registered domain (not a subdomain or IP address) under an ICANN section suffix.
+func (f *FastTLD) IsHSTSPreloadable(url string) bool {
+	res, err := f.Extract(URLParams{URL: url})
+	return err == nil && res.HostType == HostName && len(res.RegisteredDomain) != 0 &&
+		len(res.SubDomain) == 0 && res.SuffixSection == ICANNSection
+}
+
+// Superdomains returns host and each of its parent domains down to its registered domain,
+// most specific first, e.g. [a.b.example.com b.example.com example.com] for "a.b.example.com".
+//
+// Domains are returned in lower case, with internationalised label separators mapped to ".".
+// Returns nil if host is invalid, is an IP address, or has no registered domain.
+func (f *FastTLD) Superdomains(host string) []string {
+	res, err := f.Extract(URLParams{URL: host})
+	if err != nil || res.HostType != HostName || len(res.RegisteredDomain) == 0 {
+		return nil
+	}
+	host = res.Host()
+	registeredDomainStartIdx, _ := res.RegisteredDomainOffsets()
+	superdomains := []string{host}
+	for idx := 0; idx < registeredDomainStartIdx; idx++ {
+		if host[idx] == '.' {
+			superdomains = append(superdomains, host[idx+1:])
+		}
+	}
+	return superdomains
+}
+
+// ZoneCut returns the registered domain of host, where the authoritative DNS zone boundary
+// is assumed to be, and the number of labels of host below its public suffix,
+// e.g. ("example.com", 1) for "example.com" and ("example.co.uk", 3) for "a.b.example.co.uk".
+//
+// The registered domain is returned in lower case, with internationalised label separators mapped to ".".
+// Returns an error if host is invalid, is an IP address, or has no registered domain.
+func (f *FastTLD) ZoneCut(host string) (string, int, error) {
+	res, err := f.Extract(URLParams{URL: host})
+	if err != nil {
+		return "", 0, err
+	}
+	if res.IsIPAddress() {
+		return "", 0, errors.New("IP address has no zone cut")
+	}
+	if len(res.RegisteredDomain) == 0 {
+		return "", 0, errors.New("host has no registered domain")
+	}
+	cutLabels := 1
+	if len(res.SubDomain) != 0 {
+		cutLabels += strings.Count(labelSeparatorReplacer.Replace(res.SubDomain), ".") + 1
+	}
+	return labelSeparatorReplacer.Replace(res.RegisteredDomain), cutLabels, nil
+}
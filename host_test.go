@@ -0,0 +1,605 @@
+package fasttld
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type organizationalDomainTest struct {
+	includePrivateSuffix bool
+	host                 string
+	expected             string
+	err                  error
+	description          string
+}
+
+var organizationalDomainTests = []organizationalDomainTest{
+	{host: "example.com", expected: "example.com", description: "Registrable domain"},
+	{host: "mail.example.com", expected: "example.com", description: "RFC 7489 | Subdomain of organizational domain"},
+	{host: "a.b.c.example.com", expected: "example.com", description: "Multiple subdomains"},
+	{host: "Mail.Example.COM.", expected: "example.com", description: "Mixed case with trailing dot"},
+	{host: "mail。example．com", expected: "example.com", description: "Internationalised label separators"},
+	{host: "a.b.example.co.uk", expected: "example.co.uk", description: "Multi-label public suffix"},
+	{host: "example.this-tld-cannot-be-real", expected: "example.this-tld-cannot-be-real", description: "Unlisted TLD | implicit * rule"},
+	{host: "a.b.kawasaki.jp", expected: "a.b.kawasaki.jp", description: "Wildcard rule | *.kawasaki.jp"},
+	{host: "a.city.kawasaki.jp", expected: "city.kawasaki.jp", description: "Wildcard exception rule | !city.kawasaki.jp"},
+	{host: "foo.blogspot.com", expected: "blogspot.com", description: "Private suffix excluded"},
+	{includePrivateSuffix: true, host: "foo.blogspot.com", expected: "blogspot.com", description: "Private suffix ignored even if included"},
+	{includePrivateSuffix: true, host: "a.foo.global.prod.fastly.net", expected: "fastly.net", description: "Private suffix ignored even if included | multi-label"},
+	{host: "com", err: errors.New("host is a public suffix"), description: "Public suffix only"},
+	{host: "co.uk.", err: errors.New("host is a public suffix"), description: "Multi-label public suffix only"},
+	{host: "", err: errors.New("empty host"), description: "Empty host"},
+	{host: "mail..example.com", err: errors.New("invalid consecutive label separators on left-hand side of a label"), description: "Empty label"},
+	{host: "127.0.0.1", err: errors.New("IP address has no organizational domain"), description: "IPv4 address"},
+	{host: "::1", err: errors.New("IP address has no organizational domain"), description: "IPv6 address"},
+}
+
+func TestOrganizationalDomain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range organizationalDomainTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		output, err := extractor.OrganizationalDomain(test.host)
+		if output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q | %s", test.host, output, test.expected, test.description)
+		}
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v | %s", test.host, err, test.err, test.description)
+		}
+	}
+}
+
+type publicSuffixTest struct {
+	includePrivateSuffix bool
+	host                 string
+	suffix               string
+	isICANN              bool
+	description          string
+}
+
+var publicSuffixTests = []publicSuffixTest{
+	{host: "www.example.co.uk", suffix: "co.uk", isICANN: true, description: "Multi-label public suffix"},
+	{includePrivateSuffix: true, host: "a.b.foo.amazonaws.com", suffix: "com", isICANN: true, description: "Labels matched beyond longest rule"},
+	{host: "example.com", suffix: "com", isICANN: true, description: "Registered domain"},
+	{host: "com", suffix: "com", isICANN: true, description: "Public suffix only"},
+	{host: "WWW.Example.CO.UK.", suffix: "co.uk", isICANN: true, description: "Mixed case with trailing dot"},
+	{host: "www。example．co.uk", suffix: "co.uk", isICANN: true, description: "Internationalised label separators"},
+	{host: "a.b.kawasaki.jp", suffix: "b.kawasaki.jp", isICANN: true, description: "Wildcard rule | *.kawasaki.jp"},
+	{host: "a.city.kawasaki.jp", suffix: "kawasaki.jp", isICANN: true, description: "Wildcard exception rule | !city.kawasaki.jp"},
+	{host: "www.ck", suffix: "ck", isICANN: true, description: "Top level wildcard exception rule | !www.ck"},
+	{host: "a.b.ck", suffix: "b.ck", isICANN: true, description: "Top level wildcard rule | *.ck"},
+	{host: "foo.blogspot.com", suffix: "com", isICANN: true, description: "Private suffix excluded"},
+	{includePrivateSuffix: true, host: "foo.blogspot.com", suffix: "blogspot.com", description: "Private suffix included"},
+	{host: "www.食狮.公司.cn", suffix: "公司.cn", isICANN: true, description: "Internationalised public suffix"},
+	{host: "www.xn--85x722f.xn--55qx5d.cn", suffix: "xn--55qx5d.cn", isICANN: true, description: "Punycode public suffix"},
+	{host: "example.this-tld-cannot-be-real", description: "Unlisted TLD"},
+	{host: "localhost", description: "Single label"},
+	{host: "", description: "Empty host"},
+}
+
+func TestPublicSuffix(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range publicSuffixTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		suffix, isICANN := extractor.PublicSuffix(test.host)
+		if suffix != test.suffix || isICANN != test.isICANN {
+			t.Errorf("%q | Output (%q, %t) not equal to expected (%q, %t) | %s",
+				test.host, suffix, isICANN, test.suffix, test.isICANN, test.description)
+		}
+		// Same suffix as Extract
+		res, _ := extractor.Extract(URLParams{URL: test.host, DomainCase: LowerCase})
+		if labelSeparatorReplacer.Replace(res.Suffix) != suffix || (res.SuffixSection == ICANNSection) != isICANN {
+			t.Errorf("%q | Output (%q, %t) not equal to Extract output (%q, %d) | %s",
+				test.host, suffix, isICANN, res.Suffix, res.SuffixSection, test.description)
+		}
+	}
+}
+
+type isSuffixTest struct {
+	includePrivateSuffix bool
+	host                 string
+	isPublicSuffix       bool
+	isPrivateSuffix      bool
+}
+
+// derived from test/mini_public_suffix_list.dat
+var isSuffixTests = []isSuffixTest{
+	{host: "ac", isPublicSuffix: true},
+	{host: "com.ac", isPublicSuffix: true},
+	{host: "COM.AC.", isPublicSuffix: true},
+	{host: "com\u3002ac", isPublicSuffix: true},
+	{host: "example.com.ac"},
+	{host: "foo.ck", isPublicSuffix: true},
+	{host: "www.ck"},
+	{host: "example.foo.ck"},
+	{host: "org.sg", isPublicSuffix: true},
+	{host: "sg"},
+	{host: "blogspot.com"},
+	{includePrivateSuffix: true, host: "blogspot.com", isPublicSuffix: true, isPrivateSuffix: true},
+	{includePrivateSuffix: true, host: "example.blogspot.com"},
+	{includePrivateSuffix: true, host: "com.ac", isPublicSuffix: true},
+	{host: "localhost"},
+	{host: ""},
+	{host: "."},
+}
+
+func TestIsSuffix(t *testing.T) {
+	miniPSLFilePath := fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator))
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        miniPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        miniPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range isSuffixTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		if output := extractor.IsPublicSuffix(test.host); output != test.isPublicSuffix {
+			t.Errorf("%q | IsPublicSuffix output %t not equal to expected %t", test.host, output, test.isPublicSuffix)
+		}
+		if output := extractor.IsPrivateSuffix(test.host); output != test.isPrivateSuffix {
+			t.Errorf("%q | IsPrivateSuffix output %t not equal to expected %t", test.host, output, test.isPrivateSuffix)
+		}
+	}
+}
+
+type longestSuffixTest struct {
+	includePrivateSuffix bool
+	host                 string
+	suffix               string
+	ok                   bool
+}
+
+var longestSuffixTests = []longestSuffixTest{
+	{host: "co.uk", suffix: "co.uk", ok: true},
+	{host: "garbage.co.uk", suffix: "co.uk", ok: true},
+	{host: "garbage!@#.co.uk", suffix: "co.uk", ok: true},
+	{host: ".co.uk", suffix: "co.uk", ok: true},
+	{host: "a..b.co.uk", suffix: "co.uk", ok: true},
+	{host: "WWW.Example.CO.UK.", suffix: "co.uk", ok: true},
+	{host: "example\u3002co\uff0euk", suffix: "co.uk", ok: true},
+	{host: "uk", suffix: "uk", ok: true},
+	{host: "example.com.ac", suffix: "com.ac", ok: true},
+	{host: "a.b.ck", suffix: "b.ck", ok: true},
+	{host: "ck", suffix: "ck", ok: true},
+	{host: ".ck", suffix: "ck", ok: true},
+	{host: "www.ck", suffix: "ck", ok: true},
+	{host: "example.blogspot.co.uk", suffix: "co.uk", ok: true},
+	{includePrivateSuffix: true, host: "example.blogspot.co.uk", suffix: "blogspot.co.uk", ok: true},
+	{host: "", suffix: "", ok: false},
+	{host: "localhost", suffix: "", ok: false},
+	{host: "example.notatld", suffix: "", ok: false},
+	{host: "co.uk..", suffix: "", ok: false},
+}
+
+func TestLongestSuffix(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range longestSuffixTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		if suffix, ok := extractor.LongestSuffix(test.host); suffix != test.suffix || ok != test.ok {
+			t.Errorf("%q | Output (%q, %t) not equal to expected (%q, %t)", test.host, suffix, ok, test.suffix, test.ok)
+		}
+	}
+	// Extract requires a Domain
+	if _, err := extractorWithoutPrivateSuffix.Extract(URLParams{URL: "co.uk"}); err == nil {
+		t.Errorf("Extract should reject a suffix without a Domain")
+	}
+}
+
+type matchSuffixTest struct {
+	includePrivateSuffix bool
+	host                 string
+	suffixStart          int
+	isWildcard           bool
+	isException          bool
+	ok                   bool
+}
+
+var matchSuffixTests = []matchSuffixTest{
+	{host: "www.example.co.uk", suffixStart: 12, ok: true},
+	{host: "www.example.co.uk.", suffixStart: 12, ok: true},
+	{host: "co.uk", suffixStart: 0, ok: true},
+	{host: "a.example.b.ck", suffixStart: 10, isWildcard: true, ok: true},
+	{host: "b.ck", suffixStart: 0, isWildcard: true, ok: true},
+	{host: "a.www.ck", suffixStart: 6, isException: true, ok: true},
+	{host: "www.ck", suffixStart: 4, isException: true, ok: true},
+	{host: "x." + strings.Repeat("b", 40) + ".ck", suffixStart: 2, isWildcard: true, ok: true},
+	{host: "example.blogspot.co.uk", suffixStart: 17, ok: true},
+	{includePrivateSuffix: true, host: "example.blogspot.co.uk", suffixStart: 8, ok: true},
+	{host: "WWW.EXAMPLE.CO.UK", ok: false},
+	{host: "example\u3002co\uff0euk", ok: false},
+	{host: "localhost", ok: false},
+	{host: "", ok: false},
+}
+
+func TestMatchSuffix(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range matchSuffixTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		suffixStart, isWildcard, isException, ok := extractor.MatchSuffix(test.host)
+		if suffixStart != test.suffixStart || isWildcard != test.isWildcard || isException != test.isException || ok != test.ok {
+			t.Errorf("%q | Output (%d, %t, %t, %t) not equal to expected (%d, %t, %t, %t)", test.host,
+				suffixStart, isWildcard, isException, ok, test.suffixStart, test.isWildcard, test.isException, test.ok)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { extractor.MatchSuffix(test.host) }); allocs != 0 {
+			t.Errorf("%q | Expected no allocations. Got %v", test.host, allocs)
+		}
+	}
+}
+
+type registeredDomainTest struct {
+	includePrivateSuffix bool
+	host                 string
+	expected             string
+}
+
+var registeredDomainTests = []registeredDomainTest{
+	{host: "example.com", expected: "example.com"},
+	{host: "www.example.co.uk", expected: "example.co.uk"},
+	{host: "A.B.Example.CO.UK.", expected: "example.co.uk"},
+	{host: "www.example\u3002co\uff0euk\uff61", expected: "example.co.uk"},
+	{host: "www.MÜNCHEN.de", expected: "münchen.de"},
+	{host: "www.xn--mnchen-3ya.de", expected: "xn--mnchen-3ya.de"},
+	{host: "例子.中国", expected: "例子.中国"},
+	{host: "a.example.b.ck", expected: "example.b.ck"},
+	{host: "a.www.ck", expected: "www.ck"},
+	{host: "www.ck", expected: "www.ck"},
+	{host: "a.example.com.ac", expected: "example.com.ac"},
+	{host: "example.blogspot.co.uk", expected: "blogspot.co.uk"},
+	{includePrivateSuffix: true, host: "a.example.blogspot.co.uk", expected: "example.blogspot.co.uk"},
+	{includePrivateSuffix: true, host: "blogspot.co.uk", expected: ""},
+	{host: "co.uk", expected: ""},
+	{host: "b.ck", expected: ""},
+	{host: "localhost", expected: ""},
+	{host: "example.notatld", expected: ""},
+	{host: "127.0.0.1", expected: ""},
+	{host: "", expected: ""},
+	{host: "https://www.example.com", expected: ""},
+	{host: "www.example.com:8080", expected: ""},
+	{host: "www..example.com", expected: ""},
+	{host: "-example.com", expected: ""},
+}
+
+func TestRegisteredDomain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range registeredDomainTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		if output := extractor.RegisteredDomain(test.host); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.host, output, test.expected)
+		}
+	}
+}
+
+type settableCookieDomainsTest struct {
+	includePrivateSuffix bool
+	host                 string
+	expected             []string
+}
+
+var settableCookieDomainsTests = []settableCookieDomainsTest{
+	{host: "a.b.example.co.uk", expected: []string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk"}},
+	{host: "A.B.Example.CO.UK.", expected: []string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk"}},
+	{host: "a。b．example｡co.uk", expected: []string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk"}},
+	{host: "example.co.uk", expected: []string{"example.co.uk"}},
+	{host: "www.example.com", expected: []string{"www.example.com", "example.com"}},
+	{host: "a.example.blogspot.com", expected: []string{"a.example.blogspot.com", "example.blogspot.com", "blogspot.com"}},
+	{includePrivateSuffix: true, host: "a.example.blogspot.com", expected: []string{"a.example.blogspot.com", "example.blogspot.com"}},
+	{host: "co.uk", expected: []string{"co.uk"}},
+	{host: "a.b.example.this-tld-cannot-be-real", expected: []string{"a.b.example.this-tld-cannot-be-real"}},
+	{host: "127.0.0.1", expected: []string{"127.0.0.1"}},
+	{host: "[::1]", expected: []string{"::1"}},
+	{host: "example!.com", expected: nil},
+	{host: "", expected: nil},
+}
+
+func TestSettableCookieDomains(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: true,
+	})
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{
+		CacheFilePath:        testPSLFilePath,
+		IncludePrivateSuffix: false,
+	})
+	for _, test := range settableCookieDomainsTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		if output := extractor.SettableCookieDomains(test.host); !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("%q | Output %q not equal to expected %q", test.host, output, test.expected)
+		}
+	}
+}
+
+type siteForCookiesTest struct {
+	url      string
+	expected string
+}
+
+var siteForCookiesTests = []siteForCookiesTest{
+	{"https://www.example.co.uk/path", "https://example.co.uk"},
+	{"http://www.example.co.uk/path", "http://example.co.uk"},
+	{"HTTPS://user@A.B.Example.COM:8443/a?b#c", "https://example.com"},
+	{"https://www.münchen.de", "https://xn--mnchen-3ya.de"},
+	{"wss://chat.example.com/socket", "wss://example.com"},
+	{"http://127.0.0.1:8080/", "http://127.0.0.1"},
+	{"https://[::1]:8443/", "https://[::1]"},
+	{"https://localhost:3000", "https://localhost"},
+	{"https://example.this-tld-cannot-be-real", "https://example.this-tld-cannot-be-real"},
+	{"ftp://example.com", ""},
+	{"example.com", ""},
+	{"//example.com", ""},
+	{"https://example!.com", ""},
+}
+
+func TestSiteForCookies(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range siteForCookiesTests {
+		if output := extractor.SiteForCookies(test.url); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.url, output, test.expected)
+		}
+	}
+}
+
+type canonicalRegistrableDomainTest struct {
+	url      string
+	expected string
+}
+
+var canonicalRegistrableDomainTests = []canonicalRegistrableDomainTest{
+	{"example.com", "example.com"},
+	{"www.example.com.", "example.com"},
+	{"WWW.EXAMPLE.COM", "example.com"},
+	{"https://www.Example.com:443/path", "example.com"},
+	{"www。example．com", "example.com"},
+	{"www.example.co.uk", "example.co.uk"},
+	{"www.MÜNCHEN.de", "xn--mnchen-3ya.de"},
+	{"münchen.de.", "xn--mnchen-3ya.de"},
+	{"xn--mnchen-3ya.de", "xn--mnchen-3ya.de"},
+	{"www.com", "www.com"},
+	{"com", ""},
+	{"localhost", ""},
+	{"127.0.0.1", ""},
+	{"https://example!.com", ""},
+}
+
+func TestCanonicalRegistrableDomain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range canonicalRegistrableDomainTests {
+		if output := extractor.CanonicalRegistrableDomain(test.url); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.url, output, test.expected)
+		}
+	}
+}
+
+type rdapQueryDomainTest struct {
+	url      string
+	expected string
+	err      error
+}
+
+var rdapQueryDomainTests = []rdapQueryDomainTest{
+	{url: "https://www.example.co.uk/path", expected: "example.co.uk"},
+	{url: "https://www.MÜNCHEN.de", expected: "xn--mnchen-3ya.de"},
+	{url: "http://例子.中国", expected: "xn--fsqu00a.xn--fiqs8s"},
+	{url: "www.example．com.", expected: "example.com"},
+	{url: "127.0.0.1", err: errors.New("IP address has no registered domain")},
+	{url: "https://[::1]:8080", err: errors.New("IP address has no registered domain")},
+	{url: "localhost", err: errors.New("host has no registered domain")},
+	{url: "co.uk", err: errors.New("empty domain")},
+	{url: "https://example!.com", err: errors.New("empty domain")},
+}
+
+func TestRDAPQueryDomain(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range rdapQueryDomainTests {
+		output, err := extractor.RDAPQueryDomain(test.url)
+		if output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.url, output, test.expected)
+		}
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v", test.url, err, test.err)
+		}
+	}
+}
+
+type hstsPreloadableTest struct {
+	url                  string
+	includePrivateSuffix bool
+	expected             bool
+}
+
+var hstsPreloadableTests = []hstsPreloadableTest{
+	{url: "https://example.com", expected: true},
+	{url: "example.co.uk.", expected: true},
+	{url: "https://例子.中国/path", expected: true},
+	{url: "https://www.example.com", expected: false},
+	{url: "https://a.b.example.co.uk", expected: false},
+	{url: "127.0.0.1", expected: false},
+	{url: "https://[::1]:8080", expected: false},
+	{url: "co.uk", expected: false},
+	{url: "localhost", expected: false},
+	{url: "blogspot.com", expected: true},
+	{url: "blogspot.com", includePrivateSuffix: true, expected: false},
+	{url: "example.blogspot.com", includePrivateSuffix: true, expected: false},
+	{url: "https://example!.com", expected: false},
+}
+
+func TestIsHSTSPreloadable(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractorWithoutPrivateSuffix, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	extractorWithPrivateSuffix, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath, IncludePrivateSuffix: true})
+	for _, test := range hstsPreloadableTests {
+		extractor := extractorWithoutPrivateSuffix
+		if test.includePrivateSuffix {
+			extractor = extractorWithPrivateSuffix
+		}
+		if output := extractor.IsHSTSPreloadable(test.url); output != test.expected {
+			t.Errorf("%q | Output %t not equal to expected %t", test.url, output, test.expected)
+		}
+	}
+}
+
+type superdomainsTest struct {
+	host     string
+	expected []string
+}
+
+var superdomainsTests = []superdomainsTest{
+	{"example.com", []string{"example.com"}},
+	{"www.example.com", []string{"www.example.com", "example.com"}},
+	{"a.b.example.com", []string{"a.b.example.com", "b.example.com", "example.com"}},
+	{"A.B.C.Example.CO.UK.", []string{"a.b.c.example.co.uk", "b.c.example.co.uk", "c.example.co.uk", "example.co.uk"}},
+	{"a。b．example.com", []string{"a.b.example.com", "b.example.com", "example.com"}},
+	{"com", nil},
+	{"localhost", nil},
+	{"127.0.0.1", nil},
+	{"example!.com", nil},
+}
+
+func TestSuperdomains(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range superdomainsTests {
+		if output := extractor.Superdomains(test.host); !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("%q | Output %q not equal to expected %q", test.host, output, test.expected)
+		}
+	}
+}
+
+type zoneCutTest struct {
+	host             string
+	registeredDomain string
+	cutLabels        int
+	err              error
+}
+
+var zoneCutTests = []zoneCutTest{
+	{host: "example.com", registeredDomain: "example.com", cutLabels: 1},
+	{host: "www.example.com", registeredDomain: "example.com", cutLabels: 2},
+	{host: "a.b.example.co.uk", registeredDomain: "example.co.uk", cutLabels: 3},
+	{host: "A.B。Example.CO.UK.", registeredDomain: "example.co.uk", cutLabels: 3},
+	{host: "x.www.ck", registeredDomain: "www.ck", cutLabels: 2},
+	{host: "127.0.0.1", err: errors.New("IP address has no zone cut")},
+	{host: "[::1]", err: errors.New("IP address has no zone cut")},
+	{host: "localhost", err: errors.New("host has no registered domain")},
+	{host: "co.uk", err: errors.New("empty domain")},
+}
+
+func TestZoneCut(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range zoneCutTests {
+		registeredDomain, cutLabels, err := extractor.ZoneCut(test.host)
+		if registeredDomain != test.registeredDomain || cutLabels != test.cutLabels {
+			t.Errorf("%q | Output (%q, %d) not equal to expected (%q, %d)", test.host, registeredDomain, cutLabels,
+				test.registeredDomain, test.cutLabels)
+		}
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v", test.host, err, test.err)
+		}
+	}
+}
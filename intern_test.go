@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package fasttld
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestInternSuffixes(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	suffixData := func(s string) *byte { return unsafe.StringData(s) }
+
+	for _, test := range []struct {
+		urls     [2]string
+		params   URLParams
+		interned bool
+	}{
+		{[2]string{"https://www.example.co.uk", "https://other.co.uk/path"}, URLParams{InternSuffixes: true}, true},
+		{[2]string{"example.com", "EXAMPLE.COM"}, URLParams{InternSuffixes: true}, true},
+		{[2]string{"https://www.example.co.uk", "https://other.co.uk/path"}, URLParams{}, false},
+		{[2]string{"example.COM", "other.COM"}, URLParams{InternSuffixes: true, DomainCase: AsInputCase}, false},
+		{[2]string{"example.foo.ck", "other.foo.ck"}, URLParams{InternSuffixes: true}, false},
+	} {
+		var data [2]*byte
+		for i, url := range test.urls {
+			test.params.URL = url
+			res, err := extractor.Extract(test.params)
+			if err != nil {
+				t.Errorf("%q | Unexpected error %q", url, err)
+			}
+			data[i] = suffixData(res.Suffix)
+		}
+		if interned := data[0] == data[1]; interned != test.interned {
+			t.Errorf("%q | Expected interned = %t. Got %t.", test.urls, test.interned, interned)
+		}
+	}
+}
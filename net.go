@@ -1,6 +1,9 @@
 package fasttld
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // IP address lengths (bytes).
 const (
@@ -171,3 +174,15 @@ func isIPv6(s string) bool {
 	}
 	return true
 }
+
+// isIPv6WithZone returns true if s is a literal IPv6 address with an optional
+// non-empty zone ID, e.g. "fe80::1%eth0", or "fe80::1%25eth0" as described in RFC 6874.
+func isIPv6WithZone(s string) bool {
+	if zoneIdx := strings.IndexByte(s, '%'); zoneIdx != -1 {
+		if zoneIdx == len(s)-1 {
+			return false
+		}
+		s = s[0:zoneIdx]
+	}
+	return isIPv6(s)
+}
@@ -55,6 +55,30 @@ var looksLikeIPv6AddressTests = []looksLikeIPAddressTest{
 	},
 }
 
+var looksLikeIPv6AddressWithZoneTests = []looksLikeIPAddressTest{
+	{maybeIPAddress: "fe80::1",
+		isIPAddress: true,
+	},
+	{maybeIPAddress: "fe80::1%eth0",
+		isIPAddress: true,
+	},
+	{maybeIPAddress: "fe80::1%25eth0",
+		isIPAddress: true,
+	},
+	{maybeIPAddress: "fe80::1%",
+		isIPAddress: false,
+	},
+	{maybeIPAddress: "%eth0",
+		isIPAddress: false,
+	},
+	{maybeIPAddress: "fe80:::1%eth0",
+		isIPAddress: false,
+	},
+	{maybeIPAddress: "::ffff:192.168.0.1",
+		isIPAddress: true,
+	},
+}
+
 func TestIsIPv4(t *testing.T) {
 	for _, test := range looksLikeIPv4AddressTests {
 		isIPv4Address := isIPv4(test.maybeIPAddress)
@@ -74,3 +98,13 @@ func TestIsIPv6(t *testing.T) {
 		}
 	}
 }
+
+func TestIsIPv6WithZone(t *testing.T) {
+	for _, test := range append(looksLikeIPv6AddressTests, looksLikeIPv6AddressWithZoneTests...) {
+		isIPv6Address := isIPv6WithZone(test.maybeIPAddress)
+		if isIPv6Address != test.isIPAddress {
+			t.Errorf("%q | Output %t not equal to expected %t",
+				test.maybeIPAddress, isIPv6Address, test.isIPAddress)
+		}
+	}
+}
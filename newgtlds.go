@@ -0,0 +1,1250 @@
+package fasttld
+
+// Code generated by go generate; DO NOT EDIT.
+// This file was generated by robots at
+// 2023-05-07 16:11:13.069693173 +0800 +08 m=+0.055099464
+// using data from
+// https://publicsuffix.org/list/public_suffix_list.dat
+
+// newGTLDs are the new gTLDs (delegated from 2013 onwards) in the ICANN section
+// of the Public Suffix List, in both Unicode and punycode form.
+var newGTLDs = map[string]struct{}{
+	"aaa":                      {},
+	"aarp":                     {},
+	"abarth":                   {},
+	"abb":                      {},
+	"abbott":                   {},
+	"abbvie":                   {},
+	"abc":                      {},
+	"able":                     {},
+	"abogado":                  {},
+	"abudhabi":                 {},
+	"academy":                  {},
+	"accenture":                {},
+	"accountant":               {},
+	"accountants":              {},
+	"aco":                      {},
+	"actor":                    {},
+	"ads":                      {},
+	"adult":                    {},
+	"aeg":                      {},
+	"aetna":                    {},
+	"afl":                      {},
+	"africa":                   {},
+	"agakhan":                  {},
+	"agency":                   {},
+	"aig":                      {},
+	"airbus":                   {},
+	"airforce":                 {},
+	"airtel":                   {},
+	"akdn":                     {},
+	"alfaromeo":                {},
+	"alibaba":                  {},
+	"alipay":                   {},
+	"allfinanz":                {},
+	"allstate":                 {},
+	"ally":                     {},
+	"alsace":                   {},
+	"alstom":                   {},
+	"amazon":                   {},
+	"americanexpress":          {},
+	"americanfamily":           {},
+	"amex":                     {},
+	"amfam":                    {},
+	"amica":                    {},
+	"amsterdam":                {},
+	"analytics":                {},
+	"android":                  {},
+	"anquan":                   {},
+	"anz":                      {},
+	"aol":                      {},
+	"apartments":               {},
+	"app":                      {},
+	"apple":                    {},
+	"aquarelle":                {},
+	"arab":                     {},
+	"aramco":                   {},
+	"archi":                    {},
+	"army":                     {},
+	"art":                      {},
+	"arte":                     {},
+	"asda":                     {},
+	"associates":               {},
+	"athleta":                  {},
+	"attorney":                 {},
+	"auction":                  {},
+	"audi":                     {},
+	"audible":                  {},
+	"audio":                    {},
+	"auspost":                  {},
+	"author":                   {},
+	"auto":                     {},
+	"autos":                    {},
+	"avianca":                  {},
+	"aws":                      {},
+	"axa":                      {},
+	"azure":                    {},
+	"baby":                     {},
+	"baidu":                    {},
+	"banamex":                  {},
+	"bananarepublic":           {},
+	"band":                     {},
+	"bank":                     {},
+	"bar":                      {},
+	"barcelona":                {},
+	"barclaycard":              {},
+	"barclays":                 {},
+	"barefoot":                 {},
+	"bargains":                 {},
+	"baseball":                 {},
+	"basketball":               {},
+	"bauhaus":                  {},
+	"bayern":                   {},
+	"bbc":                      {},
+	"bbt":                      {},
+	"bbva":                     {},
+	"bcg":                      {},
+	"bcn":                      {},
+	"beats":                    {},
+	"beauty":                   {},
+	"beer":                     {},
+	"bentley":                  {},
+	"berlin":                   {},
+	"best":                     {},
+	"bestbuy":                  {},
+	"bet":                      {},
+	"bharti":                   {},
+	"bible":                    {},
+	"bid":                      {},
+	"bike":                     {},
+	"bing":                     {},
+	"bingo":                    {},
+	"bio":                      {},
+	"black":                    {},
+	"blackfriday":              {},
+	"blockbuster":              {},
+	"blog":                     {},
+	"bloomberg":                {},
+	"blue":                     {},
+	"bms":                      {},
+	"bmw":                      {},
+	"bnpparibas":               {},
+	"boats":                    {},
+	"boehringer":               {},
+	"bofa":                     {},
+	"bom":                      {},
+	"bond":                     {},
+	"boo":                      {},
+	"book":                     {},
+	"booking":                  {},
+	"bosch":                    {},
+	"bostik":                   {},
+	"boston":                   {},
+	"bot":                      {},
+	"boutique":                 {},
+	"box":                      {},
+	"bradesco":                 {},
+	"bridgestone":              {},
+	"broadway":                 {},
+	"broker":                   {},
+	"brother":                  {},
+	"brussels":                 {},
+	"build":                    {},
+	"builders":                 {},
+	"business":                 {},
+	"buy":                      {},
+	"buzz":                     {},
+	"bzh":                      {},
+	"cab":                      {},
+	"cafe":                     {},
+	"cal":                      {},
+	"call":                     {},
+	"calvinklein":              {},
+	"cam":                      {},
+	"camera":                   {},
+	"camp":                     {},
+	"canon":                    {},
+	"capetown":                 {},
+	"capital":                  {},
+	"capitalone":               {},
+	"car":                      {},
+	"caravan":                  {},
+	"cards":                    {},
+	"care":                     {},
+	"career":                   {},
+	"careers":                  {},
+	"cars":                     {},
+	"casa":                     {},
+	"case":                     {},
+	"cash":                     {},
+	"casino":                   {},
+	"catering":                 {},
+	"catholic":                 {},
+	"cba":                      {},
+	"cbn":                      {},
+	"cbre":                     {},
+	"cbs":                      {},
+	"center":                   {},
+	"ceo":                      {},
+	"cern":                     {},
+	"cfa":                      {},
+	"cfd":                      {},
+	"chanel":                   {},
+	"channel":                  {},
+	"charity":                  {},
+	"chase":                    {},
+	"chat":                     {},
+	"cheap":                    {},
+	"chintai":                  {},
+	"christmas":                {},
+	"chrome":                   {},
+	"church":                   {},
+	"cipriani":                 {},
+	"circle":                   {},
+	"cisco":                    {},
+	"citadel":                  {},
+	"citi":                     {},
+	"citic":                    {},
+	"city":                     {},
+	"cityeats":                 {},
+	"claims":                   {},
+	"cleaning":                 {},
+	"click":                    {},
+	"clinic":                   {},
+	"clinique":                 {},
+	"clothing":                 {},
+	"cloud":                    {},
+	"club":                     {},
+	"clubmed":                  {},
+	"coach":                    {},
+	"codes":                    {},
+	"coffee":                   {},
+	"college":                  {},
+	"cologne":                  {},
+	"comcast":                  {},
+	"commbank":                 {},
+	"community":                {},
+	"company":                  {},
+	"compare":                  {},
+	"computer":                 {},
+	"comsec":                   {},
+	"condos":                   {},
+	"construction":             {},
+	"consulting":               {},
+	"contact":                  {},
+	"contractors":              {},
+	"cooking":                  {},
+	"cookingchannel":           {},
+	"cool":                     {},
+	"corsica":                  {},
+	"country":                  {},
+	"coupon":                   {},
+	"coupons":                  {},
+	"courses":                  {},
+	"cpa":                      {},
+	"credit":                   {},
+	"creditcard":               {},
+	"creditunion":              {},
+	"cricket":                  {},
+	"crown":                    {},
+	"crs":                      {},
+	"cruise":                   {},
+	"cruises":                  {},
+	"cuisinella":               {},
+	"cymru":                    {},
+	"cyou":                     {},
+	"dabur":                    {},
+	"dad":                      {},
+	"dance":                    {},
+	"data":                     {},
+	"date":                     {},
+	"dating":                   {},
+	"datsun":                   {},
+	"day":                      {},
+	"dclk":                     {},
+	"dds":                      {},
+	"deal":                     {},
+	"dealer":                   {},
+	"deals":                    {},
+	"degree":                   {},
+	"delivery":                 {},
+	"dell":                     {},
+	"deloitte":                 {},
+	"delta":                    {},
+	"democrat":                 {},
+	"dental":                   {},
+	"dentist":                  {},
+	"desi":                     {},
+	"design":                   {},
+	"dev":                      {},
+	"dhl":                      {},
+	"diamonds":                 {},
+	"diet":                     {},
+	"digital":                  {},
+	"direct":                   {},
+	"directory":                {},
+	"discount":                 {},
+	"discover":                 {},
+	"dish":                     {},
+	"diy":                      {},
+	"dnp":                      {},
+	"docs":                     {},
+	"doctor":                   {},
+	"dog":                      {},
+	"domains":                  {},
+	"dot":                      {},
+	"download":                 {},
+	"drive":                    {},
+	"dtv":                      {},
+	"dubai":                    {},
+	"dunlop":                   {},
+	"dupont":                   {},
+	"durban":                   {},
+	"dvag":                     {},
+	"dvr":                      {},
+	"earth":                    {},
+	"eat":                      {},
+	"eco":                      {},
+	"edeka":                    {},
+	"education":                {},
+	"email":                    {},
+	"emerck":                   {},
+	"energy":                   {},
+	"engineer":                 {},
+	"engineering":              {},
+	"enterprises":              {},
+	"epson":                    {},
+	"equipment":                {},
+	"ericsson":                 {},
+	"erni":                     {},
+	"esq":                      {},
+	"estate":                   {},
+	"etisalat":                 {},
+	"eurovision":               {},
+	"eus":                      {},
+	"events":                   {},
+	"exchange":                 {},
+	"expert":                   {},
+	"exposed":                  {},
+	"express":                  {},
+	"extraspace":               {},
+	"fage":                     {},
+	"fail":                     {},
+	"fairwinds":                {},
+	"faith":                    {},
+	"family":                   {},
+	"fan":                      {},
+	"fans":                     {},
+	"farm":                     {},
+	"farmers":                  {},
+	"fashion":                  {},
+	"fast":                     {},
+	"fedex":                    {},
+	"feedback":                 {},
+	"ferrari":                  {},
+	"ferrero":                  {},
+	"fiat":                     {},
+	"fidelity":                 {},
+	"fido":                     {},
+	"film":                     {},
+	"final":                    {},
+	"finance":                  {},
+	"financial":                {},
+	"fire":                     {},
+	"firestone":                {},
+	"firmdale":                 {},
+	"fish":                     {},
+	"fishing":                  {},
+	"fit":                      {},
+	"fitness":                  {},
+	"flickr":                   {},
+	"flights":                  {},
+	"flir":                     {},
+	"florist":                  {},
+	"flowers":                  {},
+	"fly":                      {},
+	"foo":                      {},
+	"food":                     {},
+	"foodnetwork":              {},
+	"football":                 {},
+	"ford":                     {},
+	"forex":                    {},
+	"forsale":                  {},
+	"forum":                    {},
+	"foundation":               {},
+	"fox":                      {},
+	"free":                     {},
+	"fresenius":                {},
+	"frl":                      {},
+	"frogans":                  {},
+	"frontdoor":                {},
+	"frontier":                 {},
+	"ftr":                      {},
+	"fujitsu":                  {},
+	"fun":                      {},
+	"fund":                     {},
+	"furniture":                {},
+	"futbol":                   {},
+	"fyi":                      {},
+	"gal":                      {},
+	"gallery":                  {},
+	"gallo":                    {},
+	"gallup":                   {},
+	"game":                     {},
+	"games":                    {},
+	"gap":                      {},
+	"garden":                   {},
+	"gay":                      {},
+	"gbiz":                     {},
+	"gdn":                      {},
+	"gea":                      {},
+	"gent":                     {},
+	"genting":                  {},
+	"george":                   {},
+	"ggee":                     {},
+	"gift":                     {},
+	"gifts":                    {},
+	"gives":                    {},
+	"giving":                   {},
+	"glass":                    {},
+	"gle":                      {},
+	"global":                   {},
+	"globo":                    {},
+	"gmail":                    {},
+	"gmbh":                     {},
+	"gmo":                      {},
+	"gmx":                      {},
+	"godaddy":                  {},
+	"gold":                     {},
+	"goldpoint":                {},
+	"golf":                     {},
+	"goo":                      {},
+	"goodyear":                 {},
+	"goog":                     {},
+	"google":                   {},
+	"gop":                      {},
+	"got":                      {},
+	"grainger":                 {},
+	"graphics":                 {},
+	"gratis":                   {},
+	"green":                    {},
+	"gripe":                    {},
+	"grocery":                  {},
+	"group":                    {},
+	"guardian":                 {},
+	"gucci":                    {},
+	"guge":                     {},
+	"guide":                    {},
+	"guitars":                  {},
+	"guru":                     {},
+	"hair":                     {},
+	"hamburg":                  {},
+	"hangout":                  {},
+	"haus":                     {},
+	"hbo":                      {},
+	"hdfc":                     {},
+	"hdfcbank":                 {},
+	"health":                   {},
+	"healthcare":               {},
+	"help":                     {},
+	"helsinki":                 {},
+	"here":                     {},
+	"hermes":                   {},
+	"hgtv":                     {},
+	"hiphop":                   {},
+	"hisamitsu":                {},
+	"hitachi":                  {},
+	"hiv":                      {},
+	"hkt":                      {},
+	"hockey":                   {},
+	"holdings":                 {},
+	"holiday":                  {},
+	"homedepot":                {},
+	"homegoods":                {},
+	"homes":                    {},
+	"homesense":                {},
+	"honda":                    {},
+	"horse":                    {},
+	"hospital":                 {},
+	"host":                     {},
+	"hosting":                  {},
+	"hot":                      {},
+	"hoteles":                  {},
+	"hotels":                   {},
+	"hotmail":                  {},
+	"house":                    {},
+	"how":                      {},
+	"hsbc":                     {},
+	"hughes":                   {},
+	"hyatt":                    {},
+	"hyundai":                  {},
+	"ibm":                      {},
+	"icbc":                     {},
+	"ice":                      {},
+	"icu":                      {},
+	"ieee":                     {},
+	"ifm":                      {},
+	"ikano":                    {},
+	"imamat":                   {},
+	"imdb":                     {},
+	"immo":                     {},
+	"immobilien":               {},
+	"inc":                      {},
+	"industries":               {},
+	"infiniti":                 {},
+	"ing":                      {},
+	"ink":                      {},
+	"institute":                {},
+	"insurance":                {},
+	"insure":                   {},
+	"international":            {},
+	"intuit":                   {},
+	"investments":              {},
+	"ipiranga":                 {},
+	"irish":                    {},
+	"ismaili":                  {},
+	"ist":                      {},
+	"istanbul":                 {},
+	"itau":                     {},
+	"itv":                      {},
+	"jaguar":                   {},
+	"java":                     {},
+	"jcb":                      {},
+	"jeep":                     {},
+	"jetzt":                    {},
+	"jewelry":                  {},
+	"jio":                      {},
+	"jll":                      {},
+	"jmp":                      {},
+	"jnj":                      {},
+	"joburg":                   {},
+	"jot":                      {},
+	"joy":                      {},
+	"jpmorgan":                 {},
+	"jprs":                     {},
+	"juegos":                   {},
+	"juniper":                  {},
+	"kaufen":                   {},
+	"kddi":                     {},
+	"kerryhotels":              {},
+	"kerrylogistics":           {},
+	"kerryproperties":          {},
+	"kfh":                      {},
+	"kia":                      {},
+	"kids":                     {},
+	"kim":                      {},
+	"kinder":                   {},
+	"kindle":                   {},
+	"kitchen":                  {},
+	"kiwi":                     {},
+	"koeln":                    {},
+	"komatsu":                  {},
+	"kosher":                   {},
+	"kpmg":                     {},
+	"kpn":                      {},
+	"krd":                      {},
+	"kred":                     {},
+	"kuokgroup":                {},
+	"kyoto":                    {},
+	"lacaixa":                  {},
+	"lamborghini":              {},
+	"lamer":                    {},
+	"lancaster":                {},
+	"lancia":                   {},
+	"land":                     {},
+	"landrover":                {},
+	"lanxess":                  {},
+	"lasalle":                  {},
+	"lat":                      {},
+	"latino":                   {},
+	"latrobe":                  {},
+	"law":                      {},
+	"lawyer":                   {},
+	"lds":                      {},
+	"lease":                    {},
+	"leclerc":                  {},
+	"lefrak":                   {},
+	"legal":                    {},
+	"lego":                     {},
+	"lexus":                    {},
+	"lgbt":                     {},
+	"lidl":                     {},
+	"life":                     {},
+	"lifeinsurance":            {},
+	"lifestyle":                {},
+	"lighting":                 {},
+	"like":                     {},
+	"lilly":                    {},
+	"limited":                  {},
+	"limo":                     {},
+	"lincoln":                  {},
+	"link":                     {},
+	"lipsy":                    {},
+	"live":                     {},
+	"living":                   {},
+	"llc":                      {},
+	"llp":                      {},
+	"loan":                     {},
+	"loans":                    {},
+	"locker":                   {},
+	"locus":                    {},
+	"lol":                      {},
+	"london":                   {},
+	"lotte":                    {},
+	"lotto":                    {},
+	"love":                     {},
+	"lpl":                      {},
+	"lplfinancial":             {},
+	"ltd":                      {},
+	"ltda":                     {},
+	"lundbeck":                 {},
+	"luxe":                     {},
+	"luxury":                   {},
+	"madrid":                   {},
+	"maif":                     {},
+	"maison":                   {},
+	"makeup":                   {},
+	"man":                      {},
+	"management":               {},
+	"mango":                    {},
+	"map":                      {},
+	"market":                   {},
+	"marketing":                {},
+	"markets":                  {},
+	"marriott":                 {},
+	"marshalls":                {},
+	"maserati":                 {},
+	"mattel":                   {},
+	"mba":                      {},
+	"mckinsey":                 {},
+	"med":                      {},
+	"media":                    {},
+	"meet":                     {},
+	"melbourne":                {},
+	"meme":                     {},
+	"memorial":                 {},
+	"men":                      {},
+	"menu":                     {},
+	"merckmsd":                 {},
+	"miami":                    {},
+	"microsoft":                {},
+	"mini":                     {},
+	"mint":                     {},
+	"mit":                      {},
+	"mitsubishi":               {},
+	"mlb":                      {},
+	"mls":                      {},
+	"mma":                      {},
+	"mobile":                   {},
+	"moda":                     {},
+	"moe":                      {},
+	"moi":                      {},
+	"mom":                      {},
+	"monash":                   {},
+	"money":                    {},
+	"monster":                  {},
+	"mormon":                   {},
+	"mortgage":                 {},
+	"moscow":                   {},
+	"moto":                     {},
+	"motorcycles":              {},
+	"mov":                      {},
+	"movie":                    {},
+	"msd":                      {},
+	"mtn":                      {},
+	"mtr":                      {},
+	"music":                    {},
+	"mutual":                   {},
+	"nab":                      {},
+	"nagoya":                   {},
+	"natura":                   {},
+	"navy":                     {},
+	"nba":                      {},
+	"nec":                      {},
+	"netbank":                  {},
+	"netflix":                  {},
+	"network":                  {},
+	"neustar":                  {},
+	"new":                      {},
+	"news":                     {},
+	"next":                     {},
+	"nextdirect":               {},
+	"nexus":                    {},
+	"nfl":                      {},
+	"ngo":                      {},
+	"nhk":                      {},
+	"nico":                     {},
+	"nike":                     {},
+	"nikon":                    {},
+	"ninja":                    {},
+	"nissan":                   {},
+	"nissay":                   {},
+	"nokia":                    {},
+	"northwesternmutual":       {},
+	"norton":                   {},
+	"now":                      {},
+	"nowruz":                   {},
+	"nowtv":                    {},
+	"nra":                      {},
+	"nrw":                      {},
+	"ntt":                      {},
+	"nyc":                      {},
+	"obi":                      {},
+	"observer":                 {},
+	"office":                   {},
+	"okinawa":                  {},
+	"olayan":                   {},
+	"olayangroup":              {},
+	"oldnavy":                  {},
+	"ollo":                     {},
+	"omega":                    {},
+	"one":                      {},
+	"ong":                      {},
+	"onl":                      {},
+	"online":                   {},
+	"ooo":                      {},
+	"open":                     {},
+	"oracle":                   {},
+	"orange":                   {},
+	"organic":                  {},
+	"origins":                  {},
+	"osaka":                    {},
+	"otsuka":                   {},
+	"ott":                      {},
+	"ovh":                      {},
+	"page":                     {},
+	"panasonic":                {},
+	"paris":                    {},
+	"pars":                     {},
+	"partners":                 {},
+	"parts":                    {},
+	"party":                    {},
+	"passagens":                {},
+	"pay":                      {},
+	"pccw":                     {},
+	"pet":                      {},
+	"pfizer":                   {},
+	"pharmacy":                 {},
+	"phd":                      {},
+	"philips":                  {},
+	"phone":                    {},
+	"photo":                    {},
+	"photography":              {},
+	"photos":                   {},
+	"physio":                   {},
+	"pics":                     {},
+	"pictet":                   {},
+	"pictures":                 {},
+	"pid":                      {},
+	"pin":                      {},
+	"ping":                     {},
+	"pink":                     {},
+	"pioneer":                  {},
+	"pizza":                    {},
+	"place":                    {},
+	"play":                     {},
+	"playstation":              {},
+	"plumbing":                 {},
+	"plus":                     {},
+	"pnc":                      {},
+	"pohl":                     {},
+	"poker":                    {},
+	"politie":                  {},
+	"porn":                     {},
+	"pramerica":                {},
+	"praxi":                    {},
+	"press":                    {},
+	"prime":                    {},
+	"prod":                     {},
+	"productions":              {},
+	"prof":                     {},
+	"progressive":              {},
+	"promo":                    {},
+	"properties":               {},
+	"property":                 {},
+	"protection":               {},
+	"pru":                      {},
+	"prudential":               {},
+	"pub":                      {},
+	"pwc":                      {},
+	"qpon":                     {},
+	"quebec":                   {},
+	"quest":                    {},
+	"racing":                   {},
+	"radio":                    {},
+	"read":                     {},
+	"realestate":               {},
+	"realtor":                  {},
+	"realty":                   {},
+	"recipes":                  {},
+	"red":                      {},
+	"redstone":                 {},
+	"redumbrella":              {},
+	"rehab":                    {},
+	"reise":                    {},
+	"reisen":                   {},
+	"reit":                     {},
+	"reliance":                 {},
+	"ren":                      {},
+	"rent":                     {},
+	"rentals":                  {},
+	"repair":                   {},
+	"report":                   {},
+	"republican":               {},
+	"rest":                     {},
+	"restaurant":               {},
+	"review":                   {},
+	"reviews":                  {},
+	"rexroth":                  {},
+	"rich":                     {},
+	"richardli":                {},
+	"ricoh":                    {},
+	"ril":                      {},
+	"rio":                      {},
+	"rip":                      {},
+	"rocher":                   {},
+	"rocks":                    {},
+	"rodeo":                    {},
+	"rogers":                   {},
+	"room":                     {},
+	"rsvp":                     {},
+	"rugby":                    {},
+	"ruhr":                     {},
+	"run":                      {},
+	"rwe":                      {},
+	"ryukyu":                   {},
+	"saarland":                 {},
+	"safe":                     {},
+	"safety":                   {},
+	"sakura":                   {},
+	"sale":                     {},
+	"salon":                    {},
+	"samsclub":                 {},
+	"samsung":                  {},
+	"sandvik":                  {},
+	"sandvikcoromant":          {},
+	"sanofi":                   {},
+	"sap":                      {},
+	"sarl":                     {},
+	"sas":                      {},
+	"save":                     {},
+	"saxo":                     {},
+	"sbi":                      {},
+	"sbs":                      {},
+	"sca":                      {},
+	"scb":                      {},
+	"schaeffler":               {},
+	"schmidt":                  {},
+	"scholarships":             {},
+	"school":                   {},
+	"schule":                   {},
+	"schwarz":                  {},
+	"science":                  {},
+	"scot":                     {},
+	"search":                   {},
+	"seat":                     {},
+	"secure":                   {},
+	"security":                 {},
+	"seek":                     {},
+	"select":                   {},
+	"sener":                    {},
+	"services":                 {},
+	"seven":                    {},
+	"sew":                      {},
+	"sex":                      {},
+	"sexy":                     {},
+	"sfr":                      {},
+	"shangrila":                {},
+	"sharp":                    {},
+	"shaw":                     {},
+	"shell":                    {},
+	"shia":                     {},
+	"shiksha":                  {},
+	"shoes":                    {},
+	"shop":                     {},
+	"shopping":                 {},
+	"shouji":                   {},
+	"show":                     {},
+	"showtime":                 {},
+	"silk":                     {},
+	"sina":                     {},
+	"singles":                  {},
+	"site":                     {},
+	"ski":                      {},
+	"skin":                     {},
+	"sky":                      {},
+	"skype":                    {},
+	"sling":                    {},
+	"smart":                    {},
+	"smile":                    {},
+	"sncf":                     {},
+	"soccer":                   {},
+	"social":                   {},
+	"softbank":                 {},
+	"software":                 {},
+	"sohu":                     {},
+	"solar":                    {},
+	"solutions":                {},
+	"song":                     {},
+	"sony":                     {},
+	"soy":                      {},
+	"spa":                      {},
+	"space":                    {},
+	"sport":                    {},
+	"spot":                     {},
+	"srl":                      {},
+	"stada":                    {},
+	"staples":                  {},
+	"star":                     {},
+	"statebank":                {},
+	"statefarm":                {},
+	"stc":                      {},
+	"stcgroup":                 {},
+	"stockholm":                {},
+	"storage":                  {},
+	"store":                    {},
+	"stream":                   {},
+	"studio":                   {},
+	"study":                    {},
+	"style":                    {},
+	"sucks":                    {},
+	"supplies":                 {},
+	"supply":                   {},
+	"support":                  {},
+	"surf":                     {},
+	"surgery":                  {},
+	"suzuki":                   {},
+	"swatch":                   {},
+	"swiss":                    {},
+	"sydney":                   {},
+	"systems":                  {},
+	"tab":                      {},
+	"taipei":                   {},
+	"talk":                     {},
+	"taobao":                   {},
+	"target":                   {},
+	"tatamotors":               {},
+	"tatar":                    {},
+	"tattoo":                   {},
+	"tax":                      {},
+	"taxi":                     {},
+	"tci":                      {},
+	"tdk":                      {},
+	"team":                     {},
+	"tech":                     {},
+	"technology":               {},
+	"temasek":                  {},
+	"tennis":                   {},
+	"teva":                     {},
+	"thd":                      {},
+	"theater":                  {},
+	"theatre":                  {},
+	"tiaa":                     {},
+	"tickets":                  {},
+	"tienda":                   {},
+	"tiffany":                  {},
+	"tips":                     {},
+	"tires":                    {},
+	"tirol":                    {},
+	"tjmaxx":                   {},
+	"tjx":                      {},
+	"tkmaxx":                   {},
+	"tmall":                    {},
+	"today":                    {},
+	"tokyo":                    {},
+	"tools":                    {},
+	"top":                      {},
+	"toray":                    {},
+	"toshiba":                  {},
+	"total":                    {},
+	"tours":                    {},
+	"town":                     {},
+	"toyota":                   {},
+	"toys":                     {},
+	"trade":                    {},
+	"trading":                  {},
+	"training":                 {},
+	"travel":                   {},
+	"travelchannel":            {},
+	"travelers":                {},
+	"travelersinsurance":       {},
+	"trust":                    {},
+	"trv":                      {},
+	"tube":                     {},
+	"tui":                      {},
+	"tunes":                    {},
+	"tushu":                    {},
+	"tvs":                      {},
+	"ubank":                    {},
+	"ubs":                      {},
+	"unicom":                   {},
+	"university":               {},
+	"uno":                      {},
+	"uol":                      {},
+	"ups":                      {},
+	"vacations":                {},
+	"vana":                     {},
+	"vanguard":                 {},
+	"vegas":                    {},
+	"ventures":                 {},
+	"verisign":                 {},
+	"vermögensberater":         {},
+	"vermögensberatung":        {},
+	"versicherung":             {},
+	"vet":                      {},
+	"viajes":                   {},
+	"video":                    {},
+	"vig":                      {},
+	"viking":                   {},
+	"villas":                   {},
+	"vin":                      {},
+	"vip":                      {},
+	"virgin":                   {},
+	"visa":                     {},
+	"vision":                   {},
+	"viva":                     {},
+	"vivo":                     {},
+	"vlaanderen":               {},
+	"vodka":                    {},
+	"volkswagen":               {},
+	"volvo":                    {},
+	"vote":                     {},
+	"voting":                   {},
+	"voto":                     {},
+	"voyage":                   {},
+	"vuelos":                   {},
+	"wales":                    {},
+	"walmart":                  {},
+	"walter":                   {},
+	"wang":                     {},
+	"wanggou":                  {},
+	"watch":                    {},
+	"watches":                  {},
+	"weather":                  {},
+	"weatherchannel":           {},
+	"webcam":                   {},
+	"weber":                    {},
+	"website":                  {},
+	"wedding":                  {},
+	"weibo":                    {},
+	"weir":                     {},
+	"whoswho":                  {},
+	"wien":                     {},
+	"wiki":                     {},
+	"williamhill":              {},
+	"win":                      {},
+	"windows":                  {},
+	"wine":                     {},
+	"winners":                  {},
+	"wme":                      {},
+	"wolterskluwer":            {},
+	"woodside":                 {},
+	"work":                     {},
+	"works":                    {},
+	"world":                    {},
+	"wow":                      {},
+	"wtc":                      {},
+	"wtf":                      {},
+	"xbox":                     {},
+	"xerox":                    {},
+	"xfinity":                  {},
+	"xihuan":                   {},
+	"xin":                      {},
+	"xn--11b4c3d":              {},
+	"xn--1ck2e1b":              {},
+	"xn--1qqw23a":              {},
+	"xn--30rr7y":               {},
+	"xn--3bst00m":              {},
+	"xn--3ds443g":              {},
+	"xn--3pxu8k":               {},
+	"xn--42c2d9a":              {},
+	"xn--45q11c":               {},
+	"xn--4gbrim":               {},
+	"xn--55qw42g":              {},
+	"xn--55qx5d":               {},
+	"xn--5su34j936bgsg":        {},
+	"xn--5tzm5g":               {},
+	"xn--6frz82g":              {},
+	"xn--6qq986b3xl":           {},
+	"xn--80adxhks":             {},
+	"xn--80aqecdr1a":           {},
+	"xn--80asehdb":             {},
+	"xn--80aswg":               {},
+	"xn--8y0a063a":             {},
+	"xn--9dbq2a":               {},
+	"xn--9et52u":               {},
+	"xn--9krt00a":              {},
+	"xn--b4w605ferd":           {},
+	"xn--bck1b9a5dre4c":        {},
+	"xn--c1avg":                {},
+	"xn--c2br7g":               {},
+	"xn--cck2b3b":              {},
+	"xn--cckwcxetd":            {},
+	"xn--cg4bki":               {},
+	"xn--czr694b":              {},
+	"xn--czrs0t":               {},
+	"xn--czru2d":               {},
+	"xn--d1acj3b":              {},
+	"xn--eckvdtc9d":            {},
+	"xn--efvy88h":              {},
+	"xn--fct429k":              {},
+	"xn--fhbei":                {},
+	"xn--fiq228c5hs":           {},
+	"xn--fiq64b":               {},
+	"xn--fjq720a":              {},
+	"xn--flw351e":              {},
+	"xn--fzys8d69uvgm":         {},
+	"xn--g2xx48c":              {},
+	"xn--gckr3f0f":             {},
+	"xn--gk3at1e":              {},
+	"xn--hxt814e":              {},
+	"xn--i1b6b1a6a2e":          {},
+	"xn--imr513n":              {},
+	"xn--io0a7i":               {},
+	"xn--j1aef":                {},
+	"xn--jlq480n2rg":           {},
+	"xn--jvr189m":              {},
+	"xn--kcrx77d1x4a":          {},
+	"xn--kput3i":               {},
+	"xn--mgba3a3ejt":           {},
+	"xn--mgba7c0bbn0a":         {},
+	"xn--mgbaakc7dvf":          {},
+	"xn--mgbab2bd":             {},
+	"xn--mgbca7dzdo":           {},
+	"xn--mgbi4ecexp":           {},
+	"xn--mgbt3dhd":             {},
+	"xn--mk1bu44c":             {},
+	"xn--mxtq1m":               {},
+	"xn--ngbc5azd":             {},
+	"xn--ngbe9e0a":             {},
+	"xn--ngbrx":                {},
+	"xn--nqv7f":                {},
+	"xn--nqv7fs00ema":          {},
+	"xn--nyqy26a":              {},
+	"xn--otu796d":              {},
+	"xn--p1acf":                {},
+	"xn--pssy2u":               {},
+	"xn--q9jyb4c":              {},
+	"xn--qcka1pmc":             {},
+	"xn--rhqv96g":              {},
+	"xn--rovu88b":              {},
+	"xn--ses554g":              {},
+	"xn--t60b56a":              {},
+	"xn--tckwe":                {},
+	"xn--tiq49xqyj":            {},
+	"xn--unup4y":               {},
+	"xn--vermgensberater-ctb":  {},
+	"xn--vermgensberatung-pwb": {},
+	"xn--vhquv":                {},
+	"xn--vuq861b":              {},
+	"xn--w4r85el8fhu5dnra":     {},
+	"xn--w4rs40l":              {},
+	"xn--xhq521b":              {},
+	"xn--zfr164b":              {},
+	"xyz":                      {},
+	"yachts":                   {},
+	"yahoo":                    {},
+	"yamaxun":                  {},
+	"yandex":                   {},
+	"yodobashi":                {},
+	"yoga":                     {},
+	"yokohama":                 {},
+	"you":                      {},
+	"youtube":                  {},
+	"yun":                      {},
+	"zappos":                   {},
+	"zara":                     {},
+	"zero":                     {},
+	"zip":                      {},
+	"zone":                     {},
+	"zuerich":                  {},
+	"дети":                     {},
+	"католик":                  {},
+	"ком":                      {},
+	"москва":                   {},
+	"онлайн":                   {},
+	"орг":                      {},
+	"рус":                      {},
+	"сайт":                     {},
+	"קום":                      {},
+	"ابوظبي":                   {},
+	"اتصالات":                  {},
+	"ارامكو":                   {},
+	"العليان":                  {},
+	"بازار":                    {},
+	"بيتك":                     {},
+	"شبكة":                     {},
+	"عرب":                      {},
+	"كاثوليك":                  {},
+	"كوم":                      {},
+	"موقع":                     {},
+	"همراه":                    {},
+	"कॉम":                      {},
+	"नेट":                      {},
+	"संगठन":                    {},
+	"คอม":                      {},
+	"みんな":                      {},
+	"アマゾン":                     {},
+	"クラウド":                     {},
+	"グーグル":                     {},
+	"コム":                       {},
+	"ストア":                      {},
+	"セール":                      {},
+	"ファッション":                   {},
+	"ポイント":                     {},
+	"世界":                       {},
+	"中信":                       {},
+	"中文网":                      {},
+	"亚马逊":                      {},
+	"企业":                       {},
+	"佛山":                       {},
+	"信息":                       {},
+	"健康":                       {},
+	"八卦":                       {},
+	"公司":                       {},
+	"公益":                       {},
+	"商城":                       {},
+	"商店":                       {},
+	"商标":                       {},
+	"嘉里":                       {},
+	"嘉里大酒店":                    {},
+	"在线":                       {},
+	"大拿":                       {},
+	"天主教":                      {},
+	"娱乐":                       {},
+	"家電":                       {},
+	"广东":                       {},
+	"微博":                       {},
+	"慈善":                       {},
+	"我爱你":                      {},
+	"手机":                       {},
+	"招聘":                       {},
+	"政务":                       {},
+	"政府":                       {},
+	"新闻":                       {},
+	"时尚":                       {},
+	"書籍":                       {},
+	"机构":                       {},
+	"淡马锡":                      {},
+	"游戏":                       {},
+	"点看":                       {},
+	"移动":                       {},
+	"组织机构":                     {},
+	"网址":                       {},
+	"网店":                       {},
+	"网站":                       {},
+	"网络":                       {},
+	"联通":                       {},
+	"谷歌":                       {},
+	"购物":                       {},
+	"通販":                       {},
+	"集团":                       {},
+	"電訊盈科":                     {},
+	"飞利浦":                      {},
+	"食品":                       {},
+	"餐厅":                       {},
+	"香格里拉":                     {},
+	"닷넷":                       {},
+	"닷컴":                       {},
+	"삼성":                       {},
+}
@@ -0,0 +1,7 @@
+//go:build fasttld_nofallback
+
+package fasttld
+
+// hardcodedPSL is empty with the fasttld_nofallback build tag,
+// leaving the hardcoded Public Suffix List out of the binary.
+const hardcodedPSL string = ""
@@ -0,0 +1,17 @@
+//go:build fasttld_nofallback
+
+package fasttld
+
+import "testing"
+
+func TestTrieConstructHardcoded(t *testing.T) {
+	if _, err := trieConstruct(false, ""); err == nil {
+		t.Errorf("error returned by trieConstruct should not be nil")
+	}
+}
+
+func TestNewWithEmbeddedList(t *testing.T) {
+	if f, err := NewWithEmbeddedList(SuffixListParams{}); err == nil || f != nil {
+		t.Errorf("Expected NewWithEmbeddedList error without the hardcoded Public Suffix List")
+	}
+}
@@ -2,8 +2,11 @@ package fasttld
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -35,6 +38,11 @@ func processLine(rawLine string, psl suffixes, isPrivateSuffix bool) (suffixes,
 	if len(line) == 0 || strings.HasPrefix(line, "//") {
 		return psl, isPrivateSuffix
 	}
+	// rules with a trailing dot are equivalent to rules without one, as with hostnames
+	line = strings.TrimSuffix(line, ".")
+	if len(line) == 0 {
+		return psl, isPrivateSuffix
+	}
 	suffix, err := idna.ToASCII(line)
 	if err != nil {
 		// skip line if unable to convert to ascii
@@ -62,62 +70,125 @@ func processLine(rawLine string, psl suffixes, isPrivateSuffix bool) (suffixes,
 	return psl, isPrivateSuffix
 }
 
-// getPublicSuffixList retrieves Public Suffixes and Private Suffixes from Public Suffix list located at cacheFilePath.
-//
-// publicSuffixes: ICANN domains. Example: com, net, org etc.
-//
-// privateSuffixes: PRIVATE domains. Example: blogspot.co.uk, appspot.com etc.
+// getHardcodedPSL returns the contents of the hardcoded Public Suffix List file.
+func getHardcodedPSL() (string, error) {
+	if len(hardcodedPSL) == 0 {
+		return "", errors.New("hardcoded Public Suffix List excluded by fasttld_nofallback build tag")
+	}
+	return hardcodedPSL, nil
+}
+
+// newHardcodedPSL creates a new *FastTLD using data from a hardcoded Public Suffix List file.
+func newHardcodedPSL(err error, n SuffixListParams) (*FastTLD, error) {
+	log.Println(err, "Fallback to hardcoded Public Suffix List")
+	tldTrie, err := trieConstruct(n.IncludePrivateSuffix, "")
+	return &FastTLD{cacheFilePath: "", tldTrie: tldTrie, includePrivateSuffix: n.IncludePrivateSuffix,
+		resultCache: newResultCache(n.CacheSize)}, err
+}
+
+// NewWithEmbeddedList creates a new *FastTLD using the Public Suffix List compiled into the module,
+// without reading, writing or downloading any file. CacheFilePath and SuffixListURL are ignored.
 //
-// allSuffixes: Both ICANN and PRIVATE domains.
-func getPublicSuffixList(cacheFilePath string) (suffixes, error) {
-	var psl suffixes
-	b, err := os.ReadFile(cacheFilePath)
+// Returns an error if the module is built with the fasttld_nofallback build tag.
+func NewWithEmbeddedList(n SuffixListParams) (*FastTLD, error) {
+	tldTrie, err := trieConstruct(n.IncludePrivateSuffix, "")
+	if err != nil {
+		return nil, err
+	}
+	return &FastTLD{tldTrie: tldTrie, includePrivateSuffix: n.IncludePrivateSuffix, resultCache: newResultCache(n.CacheSize)}, nil
+}
+
+// NewFromReader creates a new *FastTLD using the Public Suffix List read from r, e.g. a list
+// already in memory, without reading, writing or downloading any file. r may be gzip compressed.
+// CacheFilePath and SuffixListURL are ignored, and Update() returns an error.
+func NewFromReader(r io.Reader, n SuffixListParams) (*FastTLD, error) {
+	tldTrie, err := readSuffixTrie(n.IncludePrivateSuffix, r)
 	if err != nil {
-		log.Println(err)
-		return psl, err
+		return nil, err
 	}
-	var isPrivateSuffix bool
-	for _, line := range strings.Split(string(b), "\n") {
-		psl, isPrivateSuffix = processLine(line, psl, isPrivateSuffix)
+	if tldTrie.matches.Len() == 0 && !n.AllowEmptyList {
+		return nil, ErrEmptySuffixList
 	}
-	return psl, nil
+	return &FastTLD{tldTrie: tldTrie, includePrivateSuffix: n.IncludePrivateSuffix,
+		resultCache: newResultCache(n.CacheSize)}, nil
 }
 
-// getHardcodedPublicSuffixList retrieves Public Suffixes and Private Suffixes from hardcoded Public Suffix list.
-//
-// publicSuffixes: ICANN domains. Example: com, net, org etc.
-//
-// privateSuffixes: PRIVATE domains. Example: blogspot.co.uk, appspot.com etc.
-//
-// allSuffixes: Both ICANN and PRIVATE domains.
-func getHardcodedPublicSuffixList() (suffixes, error) {
-	var psl suffixes
-	var isPrivateSuffix bool
-	for _, line := range strings.Split(hardcodedPSL, "\n") {
-		psl, isPrivateSuffix = processLine(line, psl, isPrivateSuffix)
+// gzipMagicBytes are the first bytes of any gzip compressed file
+var gzipMagicBytes = []byte{0x1f, 0x8b}
+
+// maxDecompressedPSLSize is the maximum size of a decompressed Public Suffix List,
+// about 50 times the size of the actual list.
+const maxDecompressedPSLSize = 16 << 20
+
+// gunzipIfCompressed decompresses b if it is gzip compressed, otherwise b is returned as-is.
+func gunzipIfCompressed(b []byte) ([]byte, error) {
+	if !bytes.HasPrefix(b, gzipMagicBytes) {
+		return b, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
 	}
-	return psl, nil
+	defer reader.Close()
+	// read one byte past the limit to detect oversized lists, e.g. gzip bombs
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedPSLSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxDecompressedPSLSize {
+		return nil, fmt.Errorf("decompressed public suffix list exceeds %d bytes", maxDecompressedPSLSize)
+	}
+	return decompressed, nil
 }
 
-// newHardcodedPSL creates a new *FastTLD using data from a hardcoded Public Suffix List file.
-func newHardcodedPSL(err error, n SuffixListParams) (*FastTLD, error) {
-	log.Println(err, "Fallback to hardcoded Public Suffix List")
-	tldTrie, err := trieConstruct(n.IncludePrivateSuffix, "")
-	return &FastTLD{cacheFilePath: "", tldTrie: tldTrie, includePrivateSuffix: n.IncludePrivateSuffix}, err
+// readAll reads r as byte slice, decompressing it if it is gzip compressed
+func readAll(r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return b, err
+	}
+	return gunzipIfCompressed(b)
+}
+
+// readFile reads file at filePath as byte slice, decompressing it if it is gzip compressed
+func readFile(filePath string) ([]byte, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return b, err
+	}
+	return gunzipIfCompressed(b)
 }
 
-// downloadFile downloads file from url as byte slice
-func downloadFile(url string) ([]byte, error) {
+// defaultHTTPClient downloads Public Suffix List files if no *http.Client is specified.
+// Unlike http.DefaultClient, it does not wait forever for a stuck server.
+var defaultHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// downloadFile downloads file from url as byte slice with client, or defaultHTTPClient if client is nil.
+// The download is aborted if ctx is done.
+//
+// Responses with gzip Content-Encoding and gzip compressed files are decompressed.
+func downloadFile(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	if client == nil {
+		client = defaultHTTPClient
+	}
 	// Make HTTP GET request
 	var bodyBytes []byte
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return bodyBytes, err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return bodyBytes, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK {
-		bodyBytes, err = afero.ReadAll(resp.Body)
+		// http.Client only decompresses gzip Content-Encoding transparently
+		// if it requested gzip itself, so check for gzip magic bytes instead
+		if bodyBytes, err = afero.ReadAll(resp.Body); err == nil {
+			bodyBytes, err = gunzipIfCompressed(bodyBytes)
+		}
 	} else {
 		err = errors.New("Download failed, HTTP status code : " + fmt.Sprint(resp.StatusCode))
 	}
@@ -139,30 +210,75 @@ func fileLastModifiedHours(fileinfo os.FileInfo) float64 {
 	return time.Now().Sub(fileinfo.ModTime()).Hours()
 }
 
-// update updates the local cache of Public Suffix List
-func update(file afero.File,
-	publicSuffixListSources []string) error {
+// downloadPublicSuffixList downloads the Public Suffix List with client from the first of
+// publicSuffixListSources serving a valid list with at least one rule, and returns its
+// contents and suffix trie. Nothing is written to disk.
+//
+// If ctx is done, the remaining sources are skipped, and a wrapped ctx.Err() is returned.
+func downloadPublicSuffixList(ctx context.Context, client *http.Client, includePrivateSuffix bool,
+	publicSuffixListSources []string) ([]byte, *trie, error) {
 	for _, publicSuffixListSource := range publicSuffixListSources {
-		// Write GET request body to local file
-		if bodyBytes, err := downloadFile(publicSuffixListSource); err != nil {
+		bodyBytes, err := downloadFile(ctx, client, publicSuffixListSource)
+		if err != nil {
 			log.Println(err)
-		} else {
-			if !validPSLDelimiters(bodyBytes) {
-				continue
-			}
-			if _, err := file.Seek(0, 0); err != nil {
-				log.Println(err)
-				continue
-			}
-			if _, err := file.Write(bodyBytes); err != nil {
-				log.Println(err)
-				continue
+			if ctx.Err() != nil {
+				return nil, nil, fmt.Errorf("public suffix list update cancelled: %w", ctx.Err())
 			}
-			log.Println("Public Suffix List updated.")
-			return nil
+			continue
+		}
+		if !validPSLDelimiters(bodyBytes) {
+			continue
+		}
+		tldTrie, err := readSuffixTrie(includePrivateSuffix, bytes.NewReader(bodyBytes))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if tldTrie.matches.Len() == 0 {
+			log.Println(publicSuffixListSource, ErrEmptySuffixList)
+			continue
 		}
+		return bodyBytes, tldTrie, nil
+	}
+	return nil, nil, errors.New("failed to fetch any Public Suffix List from all mirrors")
+}
+
+// downloadToFile downloads Public Suffix List from url to file at filePath with client.
+// The download is aborted if ctx is done.
+//
+// The download is validated before it replaces any existing file at filePath, so a failed
+// or invalid download leaves the existing file intact.
+func downloadToFile(ctx context.Context, client *http.Client, filePath string, url string) error {
+	bodyBytes, err := downloadFile(ctx, client, url)
+	if err != nil {
+		return err
 	}
-	return errors.New("failed to fetch any Public Suffix List from all mirrors")
+	if !validPSLDelimiters(bodyBytes) {
+		return errors.New("downloaded file is not a valid Public Suffix List")
+	}
+	return writeFileAtomic(filePath, bodyBytes)
+}
+
+// writeFileAtomic writes b to a temporary file in the same folder as filePath,
+// then renames it to filePath, so that readers never see a partially written file.
+func writeFileAtomic(filePath string, b []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpFilePath := file.Name()
+	defer os.Remove(tmpFilePath) // no-op after a successful rename
+	if _, err := file.Write(b); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFilePath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, filePath)
 }
 
 func validPSLDelimiters(contents []byte) bool {
@@ -181,33 +297,58 @@ func checkCacheFile(cacheFilePath string) (bool, float64) {
 	}
 
 	var validDelimiters bool
-	if contents, err := os.ReadFile(cacheFilePath); err == nil {
+	if contents, err := readFile(cacheFilePath); err == nil {
 		validDelimiters = validPSLDelimiters(contents)
 	}
 	return pathValidErr == nil && fileinfoErr == nil && !stat.IsDir() && validDelimiters, lastModifiedHours
 }
 
+// hasNoRules returns true if cacheFilePath is a readable file without Public Suffix List section delimiters
+// and without any rules, e.g. an empty file. Files with the delimiters are checked after their trie is built.
+func hasNoRules(includePrivateSuffix bool, cacheFilePath string) bool {
+	contents, err := readFile(cacheFilePath)
+	return err == nil && !validPSLDelimiters(contents) && suffixTrie(includePrivateSuffix, string(contents)).matches.Len() == 0
+}
+
 // Update updates the default Public Suffix list file and updates its suffix trie using the updated file.
 // If cache file path is not the same as the default cache file path, this will be a no-op.
 func (f *FastTLD) Update() error {
-	filesystem := new(afero.OsFs)
-	defaultCacheFilePath := afero.GetTempDir(filesystem, "") + defaultPSLFileName
+	return f.UpdateContext(context.Background())
+}
+
+// UpdateContext is like Update, but aborts the download if ctx is done,
+// e.g. to bound the update with a deadline or cancel it during shutdown.
+//
+// If ctx is done before the update completes, a wrapped ctx.Err() is returned,
+// and the previously loaded suffix trie is kept.
+//
+// The downloaded list must contain at least one rule. It replaces the cache file
+// atomically, and only then is the suffix trie rebuilt, so a failed update leaves
+// both the cache file and the suffix trie unchanged.
+//
+// Update and UpdateContext are safe to call concurrently with Extract(). The new suffix trie
+// is fully built before it replaces the previous one.
+func (f *FastTLD) UpdateContext(ctx context.Context) error {
+	f.updateMu.Lock()
+	defer f.updateMu.Unlock()
+	defaultCacheFilePath := defaultCacheFolderPath() + defaultPSLFileName
 
 	if f.cacheFilePath != defaultCacheFilePath {
 		return errors.New("No-op. Only default Public Suffix list file can be updated")
 	}
-	file, err := os.OpenFile(defaultCacheFilePath, os.O_CREATE|os.O_WRONLY, 0644)
+	bodyBytes, tldTrie, err := downloadPublicSuffixList(ctx, f.httpClient, f.includePrivateSuffix, publicSuffixListSources)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	if updateErr := update(file, publicSuffixListSources); updateErr != nil {
-		return updateErr
-	}
-	tldTrie, err := trieConstruct(f.includePrivateSuffix, defaultCacheFilePath)
-	if err == nil {
-		f.tldTrie = tldTrie
-		f.cacheFilePath = defaultCacheFilePath
+	// replace the cache file in one step, so that concurrent readers never see a partial list
+	if err := writeFileAtomic(defaultCacheFilePath, bodyBytes); err != nil {
+		return err
 	}
-	return err
+	log.Println("Public Suffix List updated.")
+	f.mu.Lock()
+	f.tldTrie = tldTrie
+	f.mu.Unlock()
+	f.resultCache.purge()
+	f.cacheFilePath = defaultCacheFilePath
+	return nil
 }
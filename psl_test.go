@@ -1,23 +1,31 @@
 package fasttld
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 )
 
-type getPublicSuffixListTest struct {
+type readSuffixTrieTest struct {
 	cacheFilePath string
 	expectedLists suffixes
 	hasError      bool
 }
 
-var getPublicSuffixListTests = []getPublicSuffixListTest{
+var readSuffixTrieTests = []readSuffixTrieTest{
 	{cacheFilePath: fmt.Sprintf("test%spublic_suffix_list.dat", string(os.PathSeparator)),
 		expectedLists: pslTestLists,
 		hasError:      false,
@@ -29,57 +37,221 @@ var getPublicSuffixListTests = []getPublicSuffixListTest{
 				"org.ac", "*.ck", "!www.ck", "org.sg", "blogspot.com"}},
 		hasError: false,
 	},
+	{cacheFilePath: fmt.Sprintf("test%strailing_dot_public_suffix_list.dat", string(os.PathSeparator)),
+		expectedLists: suffixes{[]string{"ac", "com.ac", "uk", "co.uk", "*.ck", "!www.ck"}, []string{"blogspot.co.uk"},
+			[]string{"ac", "com.ac", "uk", "co.uk", "*.ck", "!www.ck", "blogspot.co.uk"}},
+		hasError: false,
+	},
 	{cacheFilePath: fmt.Sprintf("test%spublic_suffix_list.dat.noexist", string(os.PathSeparator)),
 		expectedLists: suffixes{[]string{}, []string{}, []string{}},
 		hasError:      true,
 	},
 }
 
-func TestGetPublicSuffixList(t *testing.T) {
-	for _, test := range getPublicSuffixListTests {
-		suffixLists, err := getPublicSuffixList(test.cacheFilePath)
+// trieRules returns the sorted rules stored in the suffix trie rooted at node,
+// in the same lists as processLine.
+func trieRules(node *trie) suffixes {
+	psl := suffixes{[]string{}, []string{}, []string{}}
+	var walk func(node *trie)
+	walk = func(node *trie) {
+		if len(node.suffix) != 0 {
+			if node.icann {
+				psl.publicSuffixes = append(psl.publicSuffixes, node.suffix)
+			}
+			if node.privateRule {
+				psl.privateSuffixes = append(psl.privateSuffixes, node.suffix)
+			}
+			psl.allSuffixes = append(psl.allSuffixes, node.suffix)
+		}
+		node.matches.Scan(func(key string, value *trie) bool {
+			walk(value)
+			return true
+		})
+	}
+	walk(node)
+	return sortedRules(psl)
+}
+
+// sortedRules returns the lists of psl sorted, without duplicates.
+func sortedRules(psl suffixes) suffixes {
+	sorted := func(rules []string) []string {
+		output := []string{}
+		seen := make(map[string]bool)
+		for _, rule := range rules {
+			if !seen[rule] {
+				seen[rule] = true
+				output = append(output, rule)
+			}
+		}
+		sort.Strings(output)
+		return output
+	}
+	return suffixes{sorted(psl.publicSuffixes), sorted(psl.privateSuffixes), sorted(psl.allSuffixes)}
+}
+
+func TestReadSuffixTrie(t *testing.T) {
+	for _, test := range readSuffixTrieTests {
+		tldTrie, err := trieConstruct(true, test.cacheFilePath)
 		if test.hasError && err == nil {
-			t.Errorf("Expected an error. Got no error.")
+			t.Errorf("%s | Expected an error. Got no error.", test.cacheFilePath)
 		}
 		if !test.hasError && err != nil {
-			t.Errorf("Expected no error. Got an error.")
+			t.Errorf("%s | Expected no error. Got an error.", test.cacheFilePath)
 		}
-		if output := reflect.DeepEqual(suffixLists,
-			test.expectedLists); !output && (len(suffixLists.publicSuffixes)+
-			len(suffixLists.privateSuffixes)+
-			len(suffixLists.allSuffixes)+
-			len(test.expectedLists.publicSuffixes)+
-			len(test.expectedLists.privateSuffixes)+
-			len(test.expectedLists.allSuffixes)) != 0 {
-			t.Errorf("Output %q not equal to expected %q",
-				suffixLists, test.expectedLists)
+		if output, expected := trieRules(tldTrie), sortedRules(test.expectedLists); !reflect.DeepEqual(output, expected) {
+			t.Errorf("%s | Output %q not equal to expected %q", test.cacheFilePath, output, expected)
 		}
 	}
+	if _, err := readSuffixTrie(false, errReader{}); err == nil {
+		t.Errorf("Expected error for failing io.Reader")
+	}
 }
 
-func TestGetHardcodedPublicSuffixList(t *testing.T) {
-	suffixLists, err := getHardcodedPublicSuffixList()
+// errReader always fails to read.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func TestGzipPublicSuffixList(t *testing.T) {
+	plainPath := fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator))
+	expected, err := New(SuffixListParams{CacheFilePath: plainPath, IncludePrivateSuffix: true})
 	if err != nil {
-		t.Errorf("Expected no error. Got an error.")
+		t.Fatalf("Cannot read %s: %v", plainPath, err)
 	}
-	if len(suffixLists.publicSuffixes) == 0 {
-		t.Errorf("len(suffixLists.publicSuffixes) should be more than 0.")
+	// gzip magic bytes are detected regardless of file extension
+	gzipped, _ := os.ReadFile(plainPath + ".gz")
+	noExtensionPath := t.TempDir() + string(os.PathSeparator) + "public_suffix_list"
+	os.WriteFile(noExtensionPath, gzipped, 0644)
+	for _, path := range []string{plainPath + ".gz", noExtensionPath} {
+		if isValid, _ := checkCacheFile(path); !isValid {
+			t.Errorf("%s | Expected gzip compressed cache file to be valid", path)
+		}
+		extractor, err := New(SuffixListParams{CacheFilePath: path, IncludePrivateSuffix: true})
+		if err != nil || !reflect.DeepEqual(extractor.tldTrie, expected.tldTrie) {
+			t.Errorf("%s | Suffix trie not equal to suffix trie of plaintext file (%v)", path, err)
+			continue
+		}
+		readerExtractor, err := NewFromReader(bytes.NewReader(gzipped), SuffixListParams{IncludePrivateSuffix: true})
+		if err != nil || !reflect.DeepEqual(readerExtractor.tldTrie, expected.tldTrie) {
+			t.Errorf("%s | Suffix trie read from io.Reader not equal to suffix trie of plaintext file (%v)", path, err)
+			continue
+		}
+		for url, suffix := range map[string]string{
+			"https://www.example.com.ac":   "com.ac",
+			"https://a.example.ck":         "example.ck",
+			"https://www.ck":               "ck",
+			"https://example.blogspot.com": "blogspot.com",
+			"https://example.org.sg":       "org.sg",
+		} {
+			for _, f := range []*FastTLD{extractor, readerExtractor} {
+				if res, _ := f.Extract(URLParams{URL: url}); res.Suffix != suffix {
+					t.Errorf("%s | %q | Output Suffix %q not equal to expected %q", path, url, res.Suffix, suffix)
+				}
+			}
+		}
 	}
-	if len(suffixLists.privateSuffixes) == 0 {
-		t.Errorf("len(suffixLists.privateSuffixes) should be more than 0.")
+	// truncated gzip stream
+	if _, err := readAll(bytes.NewReader(gzipped[0 : len(gzipped)/2])); err == nil {
+		t.Errorf("Expected error for truncated gzip stream")
 	}
-	if len(suffixLists.allSuffixes) == 0 {
-		t.Errorf("len(suffixLists.allSuffixes) should be more than 0.")
+
+	// gzip bomb
+	for _, size := range []int{maxDecompressedPSLSize, maxDecompressedPSLSize + 1} {
+		var bomb bytes.Buffer
+		gzipWriter := gzip.NewWriter(&bomb)
+		gzipWriter.Write(make([]byte, size))
+		gzipWriter.Close()
+		if b, err := readAll(&bomb); (err != nil) != (size > maxDecompressedPSLSize) {
+			t.Errorf("%d bytes | Unexpected error %v", size, err)
+		} else if err == nil && len(b) != size {
+			t.Errorf("%d bytes | Output length %d not equal to expected %d", size, len(b), size)
+		}
 	}
 }
 
-func TestNewHardcodedPSL(t *testing.T) {
-	f, err := newHardcodedPSL(nil, SuffixListParams{})
-	if err != nil {
-		t.Errorf("newHardcodedPSL error: %q", err)
+func TestNewFromReader(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
 	}
-	if f.tldTrie.matches.Len() == 0 {
-		t.Errorf("tldTrie should not be empty")
+	contents, _ := os.ReadFile(testPSLFilePath)
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	gzipWriter.Write(contents)
+	gzipWriter.Close()
+
+	for _, includePrivateSuffix := range []bool{false, true} {
+		expected, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath, IncludePrivateSuffix: includePrivateSuffix})
+		for _, b := range [][]byte{contents, gzipped.Bytes()} {
+			extractor, err := NewFromReader(bytes.NewReader(b), SuffixListParams{CacheFilePath: "/this/path/does/not/exist",
+				IncludePrivateSuffix: includePrivateSuffix})
+			if err != nil {
+				t.Fatalf("NewFromReader error: %q", err)
+			}
+			if err := extractor.Update(); err == nil {
+				t.Errorf("Update() should fail for suffix list read from io.Reader")
+			}
+			for _, url := range []string{"https://www.example.co.uk", "https://asdf.www.ck", "https://a.b.kawasaki.jp",
+				"https://example.blogspot.com", "https://user.github.io", "https://example.this-tld-cannot-be-real"} {
+				res, err := extractor.Extract(URLParams{URL: url})
+				expectedRes, expectedErr := expected.Extract(URLParams{URL: url})
+				if !reflect.DeepEqual(res, expectedRes) || (err == nil) != (expectedErr == nil) {
+					t.Errorf("%q | Output %+v (%v) not equal to expected %+v (%v)", url, res, err, expectedRes, expectedErr)
+				}
+			}
+		}
+	}
+
+	if extractor, err := NewFromReader(errReader{}, SuffixListParams{}); err == nil || extractor != nil {
+		t.Errorf("Expected error for failing io.Reader")
+	}
+}
+
+func TestEmptySuffixList(t *testing.T) {
+	commentOnlyPSLFilePath := fmt.Sprintf("test%scomment_only_public_suffix_list.dat", string(os.PathSeparator))
+	contents, _ := os.ReadFile(commentOnlyPSLFilePath)
+	if extractor, err := New(SuffixListParams{CacheFilePath: commentOnlyPSLFilePath}); err != ErrEmptySuffixList || extractor != nil {
+		t.Errorf("Expected ErrEmptySuffixList for comment-only file. Got %v", err)
+	}
+	// files without section delimiters do not fall back to another list
+	zeroBytePSLFilePath := t.TempDir() + string(os.PathSeparator) + defaultPSLFileName
+	os.WriteFile(zeroBytePSLFilePath, []byte{}, 0644)
+	if extractor, err := New(SuffixListParams{CacheFilePath: zeroBytePSLFilePath}); err != ErrEmptySuffixList || extractor != nil {
+		t.Errorf("Expected ErrEmptySuffixList for zero-byte file. Got %v", err)
+	}
+	if extractor, err := New(SuffixListParams{CacheFilePath: zeroBytePSLFilePath, AllowEmptyList: true}); err != nil ||
+		extractor == nil || extractor.tldTrie.matches.Len() != 0 {
+		t.Errorf("Expected zero-byte file to be used as an empty suffix list. Got %v", err)
+	}
+	for _, b := range [][]byte{contents, {}, []byte("\n// comment\n\n")} {
+		if extractor, err := NewFromReader(bytes.NewReader(b), SuffixListParams{}); err != ErrEmptySuffixList || extractor != nil {
+			t.Errorf("%q | Expected ErrEmptySuffixList. Got %v", b, err)
+		}
+	}
+	// a private suffix is not a rule if private suffixes are excluded
+	privateOnly := []byte("// ===BEGIN PRIVATE DOMAINS===\nblogspot.com\n// ===END PRIVATE DOMAINS===\n")
+	if _, err := NewFromReader(bytes.NewReader(privateOnly), SuffixListParams{}); err != ErrEmptySuffixList {
+		t.Errorf("Expected ErrEmptySuffixList for PRIVATE section only. Got %v", err)
+	}
+	if _, err := NewFromReader(bytes.NewReader(privateOnly), SuffixListParams{IncludePrivateSuffix: true}); err != nil {
+		t.Errorf("Expected no error for PRIVATE section with IncludePrivateSuffix. Got %v", err)
+	}
+
+	extractor, err := New(SuffixListParams{CacheFilePath: commentOnlyPSLFilePath, AllowEmptyList: true})
+	if err != nil || extractor == nil {
+		t.Fatalf("Expected empty suffix list to be allowed. Got %v", err)
+	}
+	if res, _ := extractor.Extract(URLParams{URL: "https://www.example.com"}); res.SuffixMatched() {
+		t.Errorf("Expected no suffix for empty suffix list. Got %+v", res)
+	}
+	extractor.AddSuffix("com")
+	if res, err := extractor.Extract(URLParams{URL: "https://www.example.com"}); err != nil || res.RegisteredDomain != "example.com" {
+		t.Errorf("Expected suffix added to empty suffix list to be matched. Got %+v (%v)", res, err)
+	}
+	if extractor, err := NewFromReader(bytes.NewReader(contents), SuffixListParams{AllowEmptyList: true}); err != nil || extractor == nil {
+		t.Errorf("Expected empty suffix list from io.Reader to be allowed. Got %v", err)
 	}
 }
 
@@ -95,9 +267,41 @@ func TestDownloadFile(t *testing.T) {
 		r.Header.Get("") // removes unused parameter warning
 	}))
 	defer badServer.Close()
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	gzipWriter.Write(expectedResponse)
+	gzipWriter.Close()
+	gzipEncodingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipped.Bytes())
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer gzipEncodingServer.Close()
+	gzipFileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(gzipped.Bytes())
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer gzipFileServer.Close()
 
 	// HTTP Status Code 200
-	res, _ := downloadFile(goodServer.URL)
+	res, _ := downloadFile(context.Background(), nil, goodServer.URL)
+	if output := reflect.DeepEqual(expectedResponse,
+		res); !output {
+		t.Errorf("Output %q not equal to expected %q",
+			res, expectedResponse)
+	}
+
+	// gzip Content-Encoding
+	res, _ = downloadFile(context.Background(), nil, gzipEncodingServer.URL)
+	if output := reflect.DeepEqual(expectedResponse,
+		res); !output {
+		t.Errorf("Output %q not equal to expected %q",
+			res, expectedResponse)
+	}
+
+	// gzip compressed file
+	res, _ = downloadFile(context.Background(), nil, gzipFileServer.URL)
 	if output := reflect.DeepEqual(expectedResponse,
 		res); !output {
 		t.Errorf("Output %q not equal to expected %q",
@@ -105,13 +309,13 @@ func TestDownloadFile(t *testing.T) {
 	}
 
 	// HTTP Status Code 404
-	res, _ = downloadFile(badServer.URL)
+	res, _ = downloadFile(context.Background(), nil, badServer.URL)
 	if len(res) != 0 {
 		t.Errorf("Response should be empty.")
 	}
 
 	// Malformed URL
-	res, _ = downloadFile("!example.com")
+	res, _ = downloadFile(context.Background(), nil, "!example.com")
 	if len(res) != 0 {
 		t.Errorf("Response should be empty.")
 	}
@@ -130,8 +334,9 @@ var updateTests = []updateTest{
 
 func TestUpdate(t *testing.T) {
 	requiredComments := "// ===BEGIN ICANN DOMAINS===\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\n// ===END PRIVATE DOMAINS==="
+	goodList := strings.Replace(requiredComments, "\n", "\ncom\n", 1)
 	goodServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(requiredComments))
+		w.Write([]byte(goodList))
 		r.Header.Get("") // removes unused parameter warning
 	}))
 	defer goodServer.Close()
@@ -140,16 +345,17 @@ func TestUpdate(t *testing.T) {
 		r.Header.Get("") // removes unused parameter warning
 	}))
 	defer emptyServer.Close()
+	noRulesServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(requiredComments))
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer noRulesServer.Close()
 	badServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(404)
 		r.Header.Get("") // removes unused parameter warning
 	}))
 	defer badServer.Close()
 
-	filesystem := new(afero.MemMapFs)
-	file, _ := afero.TempFile(filesystem, "", "ioutil-test")
-	defer file.Close()
-
 	for _, test := range updateTests {
 		var primarySource, fallbackSource string
 		if test.mainServerAvailable {
@@ -165,18 +371,338 @@ func TestUpdate(t *testing.T) {
 
 		// error should only be returned if Public Suffix List with requiredComments cannot
 		// be downloaded from any of the sources.
-		err := update(file, []string{primarySource, fallbackSource})
+		body, tldTrie, err := downloadPublicSuffixList(context.Background(), nil, false, []string{primarySource, fallbackSource})
 		if test.expectError && err == nil {
-			t.Errorf("Expected update() error, got no error.")
+			t.Errorf("Expected downloadPublicSuffixList() error, got no error.")
 		}
-		if !test.expectError && err != nil {
-			t.Errorf("Expected no update() error, got an error.")
+		if !test.expectError {
+			if err != nil {
+				t.Errorf("Expected no downloadPublicSuffixList() error, got an error.")
+			} else if string(body) != goodList || tldTrie.matches.Len() != 1 {
+				t.Errorf("Expected list %q with 1 rule. Got %q with %d rules.", goodList, body, tldTrie.matches.Len())
+			}
 		}
 	}
 
+	// gzip compressed Public Suffix List
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	gzipWriter.Write([]byte(goodList))
+	gzipWriter.Close()
+	gzipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gzipped.Bytes())
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer gzipServer.Close()
+	if body, _, err := downloadPublicSuffixList(context.Background(), nil, false, []string{badServer.URL, gzipServer.URL}); err != nil {
+		t.Errorf("Expected no downloadPublicSuffixList() error, got an error.")
+	} else if string(body) != goodList {
+		t.Errorf("Expected decompressed list %q. Got %q.", goodList, body)
+	}
+
 	// None of the servers return content with requiredComments
-	if err := update(file, []string{emptyServer.URL, emptyServer.URL}); err == nil {
-		t.Errorf("Expected update() error, got no error.")
+	if _, _, err := downloadPublicSuffixList(context.Background(), nil, false, []string{emptyServer.URL, emptyServer.URL}); err == nil {
+		t.Errorf("Expected downloadPublicSuffixList() error, got no error.")
+	}
+
+	// list without rules is skipped in favour of the next source
+	if body, _, err := downloadPublicSuffixList(context.Background(), nil, false, []string{noRulesServer.URL, goodServer.URL}); err != nil {
+		t.Errorf("Expected no downloadPublicSuffixList() error, got an error.")
+	} else if string(body) != goodList {
+		t.Errorf("Expected list %q from fallback source. Got %q.", goodList, body)
+	}
+	if _, _, err := downloadPublicSuffixList(context.Background(), nil, false, []string{noRulesServer.URL}); err == nil {
+		t.Errorf("Expected downloadPublicSuffixList() error for list without rules, got no error.")
+	}
+
+	// shorter Public Suffix List replaces longer cache file entirely
+	longList := strings.Replace(requiredComments, "\n", "\ncom\nnet\norg\n", 1)
+	longServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(longList))
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer longServer.Close()
+	defer func(sources []string) { publicSuffixListSources = sources }(publicSuffixListSources)
+	cacheFilePath := useTempCacheFolder(t) + defaultPSLFileName
+	extractor := &FastTLD{cacheFilePath: cacheFilePath, tldTrie: &trie{}}
+	for _, source := range []string{longServer.URL, goodServer.URL} {
+		publicSuffixListSources = []string{source}
+		if err := extractor.Update(); err != nil {
+			t.Errorf("Expected no Update() error, got %v.", err)
+		}
+	}
+	if contents, _ := os.ReadFile(cacheFilePath); string(contents) != goodList {
+		t.Errorf("Expected cache file %q after update. Got %q.", goodList, contents)
+	}
+
+	// list without rules leaves cache file and suffix trie unchanged
+	tldTrie := extractor.tldTrie
+	publicSuffixListSources = []string{noRulesServer.URL}
+	if err := extractor.Update(); err == nil {
+		t.Errorf("Expected Update() error for list without rules, got no error.")
+	}
+	if contents, _ := os.ReadFile(cacheFilePath); string(contents) != goodList {
+		t.Errorf("Expected cache file %q to be kept. Got %q.", goodList, contents)
+	}
+	if extractor.tldTrie != tldTrie {
+		t.Errorf("Suffix trie should be kept after failed update")
+	}
+}
+
+// useTempCacheFolder points the default cache file at a fresh temporary folder for the duration of t.
+func useTempCacheFolder(t *testing.T) string {
+	cacheFolderPath := t.TempDir() + string(os.PathSeparator)
+	previous := defaultCacheFolderPath
+	t.Cleanup(func() { defaultCacheFolderPath = previous })
+	defaultCacheFolderPath = func() string { return cacheFolderPath }
+	return cacheFolderPath
+}
+
+func TestUpdateConcurrentExtract(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	contents, _ := os.ReadFile(testPSLFilePath)
+	pslServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(contents)
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer pslServer.Close()
+	defer func(sources []string) { publicSuffixListSources = sources }(publicSuffixListSources)
+	publicSuffixListSources = []string{pslServer.URL}
+
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	extractor.cacheFilePath = useTempCacheFolder(t) + defaultPSLFileName
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if res, err := extractor.Extract(URLParams{URL: "https://www.example.co.uk/path"}); err != nil ||
+					res.RegisteredDomain != "example.co.uk" {
+					t.Errorf("Unexpected result %+v during update (%v)", res, err)
+					return
+				}
+			}
+		}()
+	}
+	var updateWg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		updateWg.Add(1)
+		go func() {
+			defer updateWg.Done()
+			for j := 0; j < 2; j++ {
+				if err := extractor.Update(); err != nil {
+					t.Errorf("Expected no Update() error. Got %v.", err)
+				}
+			}
+		}()
+	}
+	updateWg.Wait()
+	close(done)
+	wg.Wait()
+}
+
+func TestNewWithSuffixListURL(t *testing.T) {
+	contents, _ := os.ReadFile(fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator)))
+	pslServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(contents)
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer pslServer.Close()
+
+	cacheFilePath := t.TempDir() + string(os.PathSeparator) + defaultPSLFileName
+	extractor, err := New(SuffixListParams{CacheFilePath: cacheFilePath, SuffixListURL: pslServer.URL})
+	if err != nil {
+		t.Errorf("Expected no error. Got %q", err)
+	}
+	if extractor.cacheFilePath != cacheFilePath {
+		t.Errorf("Expected cacheFilePath to be %q. Got %q.", cacheFilePath, extractor.cacheFilePath)
+	}
+	if numTopLevelKeys := extractor.tldTrie.matches.Len(); numTopLevelKeys != 3 {
+		t.Errorf("Expected number of top level keys to be 3. Got %d.", numTopLevelKeys)
+	}
+	if cached, _ := os.ReadFile(cacheFilePath); !reflect.DeepEqual(cached, contents) {
+		t.Errorf("Cache file contents not equal to downloaded Public Suffix List")
+	}
+
+	// existing cache file is not downloaded again
+	pslServer.Close()
+	extractor, err = New(SuffixListParams{CacheFilePath: cacheFilePath, SuffixListURL: pslServer.URL})
+	if err != nil {
+		t.Errorf("Expected no error. Got %q", err)
+	}
+	if extractor.cacheFilePath != cacheFilePath {
+		t.Errorf("Expected cacheFilePath to be %q. Got %q.", cacheFilePath, extractor.cacheFilePath)
+	}
+}
+
+func TestDownloadToFileKeepsExistingFile(t *testing.T) {
+	badServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer badServer.Close()
+	invalidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>not a public suffix list</html>"))
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer invalidServer.Close()
+	contents, _ := os.ReadFile(fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator)))
+	goodServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(contents)
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer goodServer.Close()
+
+	cacheFolderPath := t.TempDir()
+	cacheFilePath := cacheFolderPath + string(os.PathSeparator) + defaultPSLFileName
+	existing := []byte("existing cache file")
+	os.WriteFile(cacheFilePath, existing, 0644)
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, test := range []struct {
+		ctx context.Context
+		url string
+	}{
+		{context.Background(), badServer.URL},
+		{context.Background(), invalidServer.URL},
+		{cancelledCtx, goodServer.URL},
+	} {
+		if err := downloadToFile(test.ctx, nil, cacheFilePath, test.url); err == nil {
+			t.Errorf("%s | Expected an error. Got no error.", test.url)
+		}
+		if cached, _ := os.ReadFile(cacheFilePath); !reflect.DeepEqual(cached, existing) {
+			t.Errorf("%s | Existing cache file modified by failed download: %q", test.url, cached)
+		}
+	}
+	if err := downloadToFile(context.Background(), nil, cacheFilePath, goodServer.URL); err != nil {
+		t.Errorf("Expected no error. Got %q", err)
+	}
+	if cached, _ := os.ReadFile(cacheFilePath); !reflect.DeepEqual(cached, contents) {
+		t.Errorf("Cache file contents not equal to downloaded Public Suffix List")
+	}
+	// no temporary files are left behind
+	if entries, _ := os.ReadDir(cacheFolderPath); len(entries) != 1 {
+		t.Errorf("Expected only the cache file in %s. Got %d files.", cacheFolderPath, len(entries))
+	}
+
+	// NewContext passes ctx to the download
+	os.WriteFile(cacheFilePath, existing, 0644)
+	extractor, _ := NewContext(cancelledCtx, SuffixListParams{CacheFilePath: cacheFilePath, SuffixListURL: goodServer.URL})
+	if extractor != nil && extractor.cacheFilePath == cacheFilePath {
+		t.Errorf("Expected cancelled download not to be used")
+	}
+	if cached, _ := os.ReadFile(cacheFilePath); !reflect.DeepEqual(cached, existing) {
+		t.Errorf("Existing cache file modified by cancelled download: %q", cached)
+	}
+}
+
+// countingTransport counts the requests made through it.
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestHTTPClient(t *testing.T) {
+	contents, _ := os.ReadFile(fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator)))
+	pslServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(contents)
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer pslServer.Close()
+
+	// custom client is used by New
+	transport := &countingTransport{}
+	cacheFilePath := t.TempDir() + string(os.PathSeparator) + defaultPSLFileName
+	extractor, err := New(SuffixListParams{CacheFilePath: cacheFilePath, SuffixListURL: pslServer.URL,
+		HTTPClient: &http.Client{Transport: transport}})
+	if err != nil {
+		t.Errorf("Expected no error. Got %q", err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("Expected 1 request through custom HTTPClient. Got %d.", transport.requests)
+	}
+	if extractor.httpClient == nil {
+		t.Errorf("Expected HTTPClient to be kept for Update()")
+	}
+
+	// custom client timeout is respected
+	unblock := make(chan struct{})
+	stuckServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer stuckServer.Close()
+	defer close(unblock)
+	if _, err := downloadFile(context.Background(), &http.Client{Timeout: 50 * time.Millisecond}, stuckServer.URL); err == nil {
+		t.Errorf("Expected timeout error. Got no error.")
+	}
+
+	if defaultHTTPClient.Timeout == 0 {
+		t.Errorf("Default HTTP client must have a finite timeout")
+	}
+}
+
+func TestUpdateContext(t *testing.T) {
+	requiredComments := "// ===BEGIN ICANN DOMAINS===\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\n// ===END PRIVATE DOMAINS==="
+	goodServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Replace(requiredComments, "\n", "\ncom\n", 1)))
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer goodServer.Close()
+	unblock := make(chan struct{})
+	stuckServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		r.Header.Get("") // removes unused parameter warning
+	}))
+	defer stuckServer.Close()
+	defer close(unblock)
+
+	// cancelled context skips remaining sources
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if body, _, err := downloadPublicSuffixList(ctx, nil, false, []string{goodServer.URL, goodServer.URL}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected wrapped context.Canceled error. Got %v.", err)
+	} else if len(body) != 0 {
+		t.Errorf("Nothing should be downloaded after cancellation")
+	}
+
+	// deadline aborts stuck download
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := downloadPublicSuffixList(ctx, nil, false, []string{stuckServer.URL, goodServer.URL}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected wrapped context.DeadlineExceeded error. Got %v.", err)
+	}
+
+	// previously loaded trie is kept
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	tldTrie := extractor.tldTrie
+	extractor.cacheFilePath = useTempCacheFolder(t) + defaultPSLFileName
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := extractor.UpdateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected wrapped context.Canceled error. Got %v.", err)
+	}
+	if extractor.tldTrie != tldTrie {
+		t.Errorf("Suffix trie should be kept after cancelled update")
 	}
 }
 
@@ -0,0 +1,372 @@
+package fasttld
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// specialSchemes are the schemes with special parsing rules in the WHATWG URL Standard.
+var specialSchemes = []string{"ftp", "file", "http", "https", "ws", "wss"}
+
+// defaultPorts maps URL schemes to their default port numbers.
+var defaultPorts = map[string]int{
+	"coap":  5683,
+	"coaps": 5684,
+	"ftp":   21,
+	"http":  80,
+	"https": 443,
+	"ws":    80,
+	"wss":   443,
+}
+
+// CSVHeader contains the names of the fields returned by ExtractResult.CSVRecord(), in the same order.
+//
+// The field order is stable. New fields will only be appended.
+var CSVHeader = []string{"scheme", "userinfo", "subdomain", "domain", "suffix", "port", "path", "registrable_domain"}
+
+// CSVRecord returns the components of r in the order of CSVHeader, for use with encoding/csv.
+// Missing components are empty strings.
+func (r *ExtractResult) CSVRecord() []string {
+	return []string{r.Scheme, r.UserInfo, r.SubDomain, r.Domain, r.Suffix, r.Port, r.Path, r.RegisteredDomain}
+}
+
+// jsonResult is the JSON representation of ExtractResult, with the field names of CSVHeader.
+type jsonResult struct {
+	Scheme           string `json:"scheme"`
+	UserInfo         string `json:"userinfo"`
+	SubDomain        string `json:"subdomain"`
+	Domain           string `json:"domain"`
+	Suffix           string `json:"suffix"`
+	Port             string `json:"port"`
+	Path             string `json:"path"`
+	RegisteredDomain string `json:"registrable_domain"`
+}
+
+func (r *ExtractResult) jsonResult() jsonResult {
+	return jsonResult{Scheme: r.Scheme, UserInfo: r.UserInfo, SubDomain: r.SubDomain, Domain: r.Domain,
+		Suffix: r.Suffix, Port: r.Port, Path: r.Path, RegisteredDomain: r.RegisteredDomain}
+}
+
+// ToJSON returns the components of r as a JSON object, with the field names of CSVHeader in the same order,
+// e.g. {"scheme":"https://","userinfo":"",...,"registrable_domain":"example.com"}.
+// Missing components are empty strings, and are never omitted.
+func (r *ExtractResult) ToJSON() []byte {
+	b, _ := json.Marshal(r.jsonResult())
+	return b
+}
+
+// ToJSONIndent is like ToJSON but indents the JSON object, as in json.MarshalIndent.
+func (r *ExtractResult) ToJSONIndent(prefix, indent string) []byte {
+	b, _ := json.MarshalIndent(r.jsonResult(), prefix, indent)
+	return b
+}
+
+// schemeName returns the lower case scheme of r without its delimiter, e.g. "https".
+//
+// Returns an empty string if r has no scheme, or if the scheme is empty (e.g. "//").
+func (r *ExtractResult) schemeName() string {
+	if colonIdx := strings.IndexByte(r.Scheme, ':'); colonIdx != -1 {
+		return strings.ToLower(r.Scheme[0:colonIdx])
+	}
+	return ""
+}
+
+// SchemeDelim returns the delimiter between the scheme name of r and the rest of the URL,
+// e.g. "://" for "https://" and "//" for protocol-relative URLs, so that the URL
+// can be reconstructed losslessly.
+//
+// Returns ":" for opaque schemes like "mailto:" and "tel:", and an empty string if r has no scheme.
+func (r *ExtractResult) SchemeDelim() string {
+	if delimIdx := strings.IndexAny(r.Scheme, `:/\`); delimIdx != -1 {
+		return r.Scheme[delimIdx:]
+	}
+	return ""
+}
+
+// SchemeIs reports whether the scheme of r matches any of schemes, ignoring case.
+//
+// schemes are scheme names without delimiters, e.g. SchemeIs("http", "https").
+func (r *ExtractResult) SchemeIs(schemes ...string) bool {
+	scheme := r.schemeName()
+	if len(scheme) == 0 {
+		return false
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SuffixMatched reports whether the Suffix of r was matched by a Public Suffix List rule.
+//
+// This is false if Suffix is a URLParams.UnknownSuffixPlaceholder.
+func (r *ExtractResult) SuffixMatched() bool {
+	return r.SuffixSection != NoSection
+}
+
+// EffectiveSLD returns the effective second-level domain (eSLD) of r, which is the label directly
+// below its public suffix, e.g. "example" for "a.b.example.co.uk", regardless of any SubDomain.
+//
+// The eSLD is the Domain of r, and RegisteredDomain is the eSLD followed by Suffix.
+// Returns an empty string if r is an IP address, or if its Suffix was not matched
+// by a Public Suffix List rule (see SuffixMatched).
+func (r *ExtractResult) EffectiveSLD() string {
+	if r.HostType != HostName || !r.SuffixMatched() {
+		return ""
+	}
+	return r.Domain
+}
+
+// IsIPAddress reports whether the host of r is an IPv4 or IPv6 address,
+// which has no SubDomain or Suffix.
+func (r *ExtractResult) IsIPAddress() bool {
+	return r.HostType == IPv4 || r.HostType == IPv6
+}
+
+// Host returns the hostname or IP address of r, with label separators normalized to ".".
+//
+// IPv6 addresses are returned without square brackets.
+func (r *ExtractResult) Host() string {
+	switch r.HostType {
+	case IPv4:
+		return labelSeparatorReplacer.Replace(r.Domain)
+	case IPv6:
+		return r.Domain
+	}
+	suffix := r.Suffix
+	if !r.SuffixMatched() {
+		suffix = ""
+	}
+	var sb strings.Builder
+	for _, component := range [...]string{r.SubDomain, r.Domain, suffix} {
+		if len(component) == 0 {
+			continue
+		}
+		if sb.Len() != 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(component)
+	}
+	return labelSeparatorReplacer.Replace(sb.String())
+}
+
+// String reassembles the components of r into a URL, e.g. "https://user@www.example.com:8443/path?q=1".
+// Empty components are omitted, and IPv6 addresses are enclosed in square brackets.
+//
+// The host is formatted as in Host(). Extracting the returned URL gives the same components as r.
+func (r *ExtractResult) String() string {
+	var sb strings.Builder
+	if r.IsBlob {
+		sb.WriteString(blobScheme)
+	}
+	sb.WriteString(r.Scheme)
+	if len(r.UserInfo) != 0 {
+		sb.WriteString(r.UserInfo)
+		sb.WriteByte('@')
+	}
+	if r.HostType == IPv6 {
+		sb.WriteByte('[')
+		sb.WriteString(r.Host())
+		sb.WriteByte(']')
+	} else if len(r.PackageName) != 0 {
+		sb.WriteString(r.PackageName)
+	} else {
+		sb.WriteString(r.Host())
+	}
+	if len(r.Port) != 0 {
+		sb.WriteByte(':')
+		sb.WriteString(r.Port)
+	}
+	if len(r.Path) != 0 && !endOfHostWithPortDelimitersSet.contains(r.Path[0]) && r.SchemeDelim() != ":" {
+		// Path of scp-like shorthand "user@host:path"
+		sb.WriteByte(':')
+	}
+	sb.WriteString(r.Path)
+	if len(r.Query) != 0 || r.HasQuery {
+		sb.WriteByte('?')
+		sb.WriteString(r.Query)
+	}
+	if len(r.Fragment) != 0 || r.HasFragment {
+		sb.WriteByte('#')
+		sb.WriteString(r.Fragment)
+	}
+	return sb.String()
+}
+
+// RegisteredDomainOffsets returns the start and end byte offsets of RegisteredDomain in Host(),
+// e.g. for highlighting the registered domain.
+//
+// Returns (-1, -1) if r has no RegisteredDomain, or if Host() is an IP address.
+func (r *ExtractResult) RegisteredDomainOffsets() (int, int) {
+	if r.HostType != HostName || len(r.RegisteredDomain) == 0 {
+		return -1, -1
+	}
+	host := r.Host()
+	registeredDomain := labelSeparatorReplacer.Replace(r.RegisteredDomain)
+	if !strings.HasSuffix(host, registeredDomain) {
+		return -1, -1
+	}
+	return len(host) - len(registeredDomain), len(host)
+}
+
+// VHostKey returns the outermost (leftmost) SubDomain label and RegisteredDomain of r,
+// e.g. "a.example.com" for "a.b.example.com", for virtual host routing.
+//
+// Returns RegisteredDomain if r has no SubDomain, or an empty string if r has no RegisteredDomain.
+// Label separators are normalized to ".".
+func (r *ExtractResult) VHostKey() string {
+	registeredDomain := labelSeparatorReplacer.Replace(r.RegisteredDomain)
+	if len(registeredDomain) == 0 || r.HostType != HostName {
+		return ""
+	}
+	if len(r.SubDomain) == 0 {
+		return registeredDomain
+	}
+	subDomain := labelSeparatorReplacer.Replace(r.SubDomain)
+	if sepIdx := strings.IndexByte(subDomain, '.'); sepIdx != -1 {
+		subDomain = subDomain[0:sepIdx]
+	}
+	return subDomain + "." + registeredDomain
+}
+
+// normalizeHost converts hostname host to lower case punycode,
+// and encloses IPv6 address host in square brackets.
+func normalizeHost(host string, hostType HostType) string {
+	switch hostType {
+	case IPv6:
+		return "[" + strings.ToLower(host) + "]"
+	case IPv4:
+		return host
+	}
+	if asPunyCode, err := idnaToPuny.ToASCII(host); err == nil {
+		return asPunyCode
+	}
+	return toLowerASCII(host)
+}
+
+// OriginKey returns the origin of r as "scheme://host:port", for use as an HTTP cache key.
+//
+// The host is converted to lower case punycode, and the port is omitted if it is
+// the default port of the scheme. UserInfo and Path are excluded.
+//
+// If r has no scheme, the origin is returned as "//host:port".
+func (r *ExtractResult) OriginKey() string {
+	var sb strings.Builder
+	r.writeOrigin(&sb)
+	return sb.String()
+}
+
+// writeOrigin writes the origin of r, as returned by OriginKey, to w.
+func (r *ExtractResult) writeOrigin(w io.Writer) {
+	scheme := r.schemeName()
+	io.WriteString(w, scheme)
+	if len(scheme) != 0 {
+		io.WriteString(w, ":")
+	}
+	io.WriteString(w, "//")
+	io.WriteString(w, normalizeHost(r.Host(), r.HostType))
+	if port, err := strconv.Atoi(r.Port); err == nil {
+		if defaultPort, ok := defaultPorts[scheme]; !ok || port != defaultPort {
+			var buf [8]byte
+			w.Write(strconv.AppendInt(append(buf[:0], ':'), int64(port), 10))
+		}
+	}
+}
+
+// Hash returns the 64-bit FNV-1a hash of the origin of r (see OriginKey) followed by its Path,
+// Query and Fragment, for use as a compact dedup key, e.g. in map[uint64]struct{}.
+//
+// Scheme and host case, and default ports, do not affect the hash. UserInfo is excluded.
+// Path, Query and Fragment are hashed with their "?" and "#" delimiters, including an empty
+// query or fragment (see HasQuery and HasFragment), so results extracted with and without
+// URLParams.SplitPath have the same hash. The hash is stable across runs and processes.
+func (r *ExtractResult) Hash() uint64 {
+	h := fnv.New64a()
+	r.writeOrigin(h)
+	io.WriteString(h, r.Path)
+	if len(r.Query) != 0 || r.HasQuery {
+		io.WriteString(h, "?")
+		io.WriteString(h, r.Query)
+	}
+	if len(r.Fragment) != 0 || r.HasFragment {
+		io.WriteString(h, "#")
+		io.WriteString(h, r.Fragment)
+	}
+	return h.Sum64()
+}
+
+// DNSName returns the hostname of r as a fully qualified domain name in lower case punycode (ACE),
+// with a trailing dot, e.g. "www.xn--mnchen-3ya.de." for "www.münchen.de", for DNS queries.
+//
+// Returns an error if r has no hostname, or if the hostname cannot be resolved by DNS
+// (e.g. it has invalid characters, or labels longer than 63 bytes).
+func (r *ExtractResult) DNSName() (string, error) {
+	if r.HostType != HostName {
+		return "", errors.New("not a hostname")
+	}
+	host, err := idnaDNSName.ToASCII(r.Host())
+	if err != nil {
+		return "", err
+	}
+	return host + ".", nil
+}
+
+// reverseDNSSuffixes maps the reverse DNS suffixes to the number of labels
+// preceding them in a complete PTR name.
+var reverseDNSSuffixes = map[string]int{
+	"in-addr.arpa": 4,
+	"ip6.arpa":     32,
+}
+
+// IsReverseDNS reports whether r is a reverse DNS (PTR) name under in-addr.arpa or ip6.arpa.
+func (r *ExtractResult) IsReverseDNS() bool {
+	if r.HostType != HostName {
+		return false
+	}
+	_, ok := reverseDNSSuffixes[strings.ToLower(labelSeparatorReplacer.Replace(r.Suffix))]
+	return ok
+}
+
+// ReverseDNSAddr returns the IP address encoded in reverse DNS (PTR) name r,
+// e.g. 127.0.0.1 for "1.0.0.127.in-addr.arpa".
+//
+// Returns the zero netip.Addr if r is not a complete PTR name for a single IP address.
+func (r *ExtractResult) ReverseDNSAddr() netip.Addr {
+	if !r.IsReverseDNS() {
+		return netip.Addr{}
+	}
+	suffix := strings.ToLower(labelSeparatorReplacer.Replace(r.Suffix))
+	host := strings.ToLower(r.Host())
+	labels := strings.Split(strings.TrimSuffix(host[0:len(host)-len(suffix)], "."), ".")
+	if len(labels) != reverseDNSSuffixes[suffix] {
+		return netip.Addr{}
+	}
+	reverse(labels)
+
+	var sb strings.Builder
+	if suffix == "in-addr.arpa" {
+		sb.WriteString(strings.Join(labels, "."))
+	} else {
+		for i, label := range labels {
+			if len(label) != 1 {
+				return netip.Addr{}
+			}
+			if i != 0 && i%4 == 0 {
+				sb.WriteByte(':')
+			}
+			sb.WriteString(label)
+		}
+	}
+	addr, err := netip.ParseAddr(sb.String())
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr
+}
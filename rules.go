@@ -0,0 +1,293 @@
+package fasttld
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/tidwall/hashmap"
+	"golang.org/x/net/idna"
+)
+
+// ValidateSuffixRule checks that rule is a well-formed Public Suffix List rule.
+//
+// A rule consists of labels separated by ".", optionally prefixed by "!" for exception
+// rules (e.g. !www.ck) or by a "*" label for wildcard rules (e.g. *.ck). Labels must be valid
+// IDNA labels in Unicode or punycode form. A single trailing dot is ignored.
+func ValidateSuffixRule(rule string) error {
+	if len(rule) == 0 {
+		return errors.New("empty rule")
+	}
+	if strings.TrimSpace(rule) != rule {
+		return errors.New("rule has leading or trailing whitespace")
+	}
+	if strings.HasPrefix(rule, "//") {
+		return errors.New("rule is a comment")
+	}
+	// a single trailing dot is allowed, as with hostnames
+	rule = strings.TrimSuffix(rule, ".")
+	isException := strings.HasPrefix(rule, "!")
+	if isException {
+		rule = rule[1:]
+	}
+	labels := strings.Split(rule, ".")
+	for idx, label := range labels {
+		if len(label) == 0 {
+			return errors.New("rule has empty label")
+		}
+		if label == "*" {
+			if idx != 0 {
+				return errors.New("wildcard is not the leftmost label of rule")
+			}
+			if isException {
+				return errors.New("exception rule has wildcard")
+			}
+			if len(labels) == 1 {
+				return errors.New("wildcard rule has no labels after wildcard")
+			}
+			continue
+		}
+		if strings.ContainsAny(label, "*!") {
+			return errors.New("wildcard or exception marker is not a whole label")
+		}
+	}
+	if isException && len(labels) == 1 {
+		return errors.New("exception rule has only one label")
+	}
+	if strings.HasPrefix(rule, "*.") {
+		rule = rule[2:]
+	}
+	if _, err := idna.Registration.ToASCII(rule); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseSuffixRule splits Public Suffix List rule into its labels, without the wildcard label
+// or exception marker, e.g. "!www.ck" is split into [www ck] with isException = true,
+// and "*.ck" is split into [ck] with isWildcard = true. A single trailing dot is ignored.
+//
+// ParseSuffixRule does not check that rule is well-formed; see ValidateSuffixRule.
+func ParseSuffixRule(rule string) (labels []string, isWildcard, isException bool) {
+	rule = strings.TrimSuffix(rule, ".")
+	if isException = strings.HasPrefix(rule, "!"); isException {
+		rule = rule[1:]
+	}
+	if isWildcard = strings.HasPrefix(rule, "*."); isWildcard {
+		rule = rule[2:]
+	}
+	if len(rule) == 0 {
+		return nil, isWildcard, isException
+	}
+	return strings.Split(rule, "."), isWildcard, isException
+}
+
+// TrieBuilder builds a *FastTLD incrementally from Public Suffix List lines,
+// e.g. while streaming a Public Suffix List file over the network.
+type TrieBuilder struct {
+	tldTrie              *trie
+	includePrivateSuffix bool
+	isPrivateSuffix      bool
+	lines                int // number of lines added so far
+}
+
+// NewTrieBuilder returns an empty TrieBuilder.
+//
+// If includePrivateSuffix = true, rules after the "// ===BEGIN PRIVATE DOMAINS===" line are
+// included as private suffixes (e.g. blogspot.com). Otherwise they are skipped.
+func NewTrieBuilder(includePrivateSuffix bool) *TrieBuilder {
+	var m hashmap.Map[string, *trie]
+	return &TrieBuilder{tldTrie: &trie{matches: m}, includePrivateSuffix: includePrivateSuffix}
+}
+
+// Add adds a line of a Public Suffix List file, in the same format as the file loader.
+// Blank lines and comments are skipped, and rules are ICANN section rules until the
+// "// ===BEGIN PRIVATE DOMAINS===" line is added.
+func (b *TrieBuilder) Add(rule string) {
+	var psl suffixes
+	b.lines++
+	psl, b.isPrivateSuffix = processLine(rule, psl, b.isPrivateSuffix)
+	for _, suffix := range psl.publicSuffixes {
+		b.insert(suffix, false, b.lines)
+	}
+	if b.includePrivateSuffix {
+		for _, suffix := range psl.privateSuffixes {
+			b.insert(suffix, true, b.lines)
+		}
+	}
+}
+
+// insert stores suffix from Public Suffix List line number line in the trie.
+func (b *TrieBuilder) insert(suffix string, private bool, line int) {
+	insertSuffix(b.tldTrie, suffix, private, line)
+}
+
+// insertSuffix stores suffix from Public Suffix List line number line in tldTrie.
+// Returns the labels of suffix in reverse-order.
+func insertSuffix(tldTrie *trie, suffix string, private bool, line int) []string {
+	sp := strings.Split(suffix, ".")
+	reverse(sp)
+	node := nestedDict(tldTrie, sp, private)
+	node.suffix = suffix
+	if node.line == 0 {
+		node.line = line
+	}
+	return sp
+}
+
+// removeSuffix deletes suffix from tldTrie, along with the nodes that no longer lead to any suffix.
+func removeSuffix(tldTrie *trie, suffix string) {
+	keys := strings.Split(suffix, ".")
+	reverse(keys)
+	path := []*trie{tldTrie}
+	node := tldTrie
+	for _, key := range keys {
+		var ok bool
+		if node, ok = node.matches.Get(key); !ok {
+			return
+		}
+		path = append(path, node)
+	}
+	if len(node.suffix) == 0 {
+		// no suffix ends at this node
+		return
+	}
+	node.end, node.icann, node.privateRule, node.suffix, node.line = false, false, false, "", 0
+	if len(keys) == 2 && keys[1] == "*" && len(path[1].suffix) == 0 {
+		// parent of a top level wildcard rule is no longer an eTLD
+		path[1].end, path[1].icann, path[1].privateRule = false, false, false
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if path[i+1].matches.Len() == 0 && !path[i+1].end {
+			path[i].matches.Delete(keys[i])
+			if strings.HasPrefix(keys[i], "!") {
+				path[i].exceptions.Delete(keys[i][1:])
+			}
+			continue
+		}
+		// the removed rule may have been the only ICANN section path through this node
+		private := !path[i+1].icann
+		if private {
+			path[i+1].matches.Scan(func(key string, value *trie) bool {
+				private = value.private
+				return private
+			})
+		}
+		path[i+1].private = private
+	}
+}
+
+// flagWildcardParent flags parent of top level wildcard rule wildcard as an eTLD,
+// in the same section as wildcard unless a rule ends at parent.
+func flagWildcardParent(parent, wildcard *trie) {
+	parent.end = true
+	if !wildcard.private {
+		parent.icann = true
+	} else if len(parent.suffix) == 0 {
+		parent.privateRule = true
+	}
+}
+
+// trie returns the trie built so far, with the parents of top level wildcard rules flagged as eTLDs.
+func (b *TrieBuilder) trie() *trie {
+	b.tldTrie.matches.Scan(func(key string, value *trie) bool {
+		if wildcard, ok := value.matches.Get("*"); ok {
+			flagWildcardParent(value, wildcard)
+		}
+		return true
+	})
+	return b.tldTrie
+}
+
+// Build returns a *FastTLD that extracts URLs using the rules added so far.
+//
+// The TrieBuilder must not be used after calling Build.
+func (b *TrieBuilder) Build() *FastTLD {
+	return &FastTLD{tldTrie: b.trie(), includePrivateSuffix: b.includePrivateSuffix}
+}
+
+// AddSuffix adds Public Suffix List rule to the suffix list at runtime, e.g. "corp.internal",
+// "*.corp.internal" or "!www.corp.internal", in the same format as the file loader.
+// Returns an error if rule is not well-formed; see ValidateSuffixRule.
+//
+// Added rules are PRIVATE section rules, even if IncludePrivateSuffix = false, as they are not
+// from the ICANN section of the Public Suffix List. They are lost when Update() replaces the suffix list.
+//
+// AddSuffix is safe to call concurrently with Extract().
+func (f *FastTLD) AddSuffix(rule string) error {
+	if err := ValidateSuffixRule(rule); err != nil {
+		return err
+	}
+	var psl suffixes
+	psl, _ = processLine(rule, psl, true)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.resultCache.purge()
+	for _, suffix := range psl.privateSuffixes {
+		if sp := insertSuffix(f.tldTrie, suffix, true, 0); len(sp) == 2 && sp[1] == "*" {
+			parent, _ := f.tldTrie.matches.Get(sp[0])
+			wildcard, _ := parent.matches.Get("*")
+			flagWildcardParent(parent, wildcard)
+		}
+	}
+	return nil
+}
+
+// RemoveSuffix removes Public Suffix List rule from the suffix list at runtime, in the same format
+// as AddSuffix(). The rule is removed from both sections of the Public Suffix List.
+// Rules which are not in the suffix list are ignored.
+// Returns an error if rule is not well-formed; see ValidateSuffixRule.
+//
+// RemoveSuffix is safe to call concurrently with Extract().
+func (f *FastTLD) RemoveSuffix(rule string) error {
+	if err := ValidateSuffixRule(rule); err != nil {
+		return err
+	}
+	var psl suffixes
+	psl, _ = processLine(rule, psl, false)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.resultCache.purge()
+	for _, suffix := range psl.publicSuffixes {
+		removeSuffix(f.tldTrie, suffix)
+	}
+	return nil
+}
+
+// SuffixSourceLine returns the line number (starting from 1) of the Public Suffix List rule
+// that suffix was matched by, e.g. an extracted ExtractResult.Suffix, to trace an extraction
+// back to the Public Suffix List. Suffixes matched by wildcard rules (e.g. "*.ck") return the
+// line of the wildcard rule. If suffix is defined by multiple rules, the first line is returned.
+//
+// Returns -1 if suffix is not matched by any rule.
+func (f *FastTLD) SuffixSourceLine(suffix string) int {
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(labelSeparatorReplacer.Replace(suffix)), "."), ".")
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	node := f.tldTrie
+	for idx := len(labels) - 1; idx >= 0; idx-- {
+		label := labels[idx]
+		if wildcard, ok := node.matches.Get("*"); ok {
+			// wildcard rules match exactly one more label, unless it has an exception rule
+			if _, ok := node.exceptions.Get(label); ok || idx != 0 {
+				return -1
+			}
+			return wildcard.line
+		}
+		val, ok := node.matches.Get(label)
+		if !ok {
+			return -1
+		}
+		node = val
+	}
+	if !node.end {
+		return -1
+	}
+	if node.line == 0 {
+		// parent of a top level wildcard rule, e.g. "ck" for "*.ck"
+		if wildcard, ok := node.matches.Get("*"); ok {
+			return wildcard.line
+		}
+		return -1
+	}
+	return node.line
+}
@@ -0,0 +1,425 @@
+package fasttld
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type validateSuffixRuleTest struct {
+	rule        string
+	err         error
+	description string
+}
+
+var validateSuffixRuleTests = []validateSuffixRuleTest{
+	{rule: "com", description: "Single label"},
+	{rule: "co.uk", description: "Multiple labels"},
+	{rule: "*.ck", description: "Wildcard rule"},
+	{rule: "*.kawasaki.jp", description: "Wildcard rule with multiple labels"},
+	{rule: "!www.ck", description: "Exception rule"},
+	{rule: "!city.kawasaki.jp", description: "Exception rule with multiple labels"},
+	{rule: "敎育.hk", description: "Unicode label"},
+	{rule: "xn--lcvr32d.hk", description: "Punycode label"},
+	{rule: "blogspot.com", description: "Private rule"},
+	{rule: "", err: errors.New("empty rule"), description: "Empty rule"},
+	{rule: " com", err: errors.New("rule has leading or trailing whitespace"), description: "Leading whitespace"},
+	{rule: "// comment", err: errors.New("rule is a comment"), description: "Comment"},
+	{rule: "a*.b", err: errors.New("wildcard or exception marker is not a whole label"), description: "Bad wildcard"},
+	{rule: "a.*.b", err: errors.New("wildcard is not the leftmost label of rule"), description: "Wildcard not leftmost"},
+	{rule: "*", err: errors.New("wildcard rule has no labels after wildcard"), description: "Wildcard only"},
+	{rule: "!*.ck", err: errors.New("exception rule has wildcard"), description: "Exception wildcard"},
+	{rule: "!ck", err: errors.New("exception rule has only one label"), description: "Exception rule with one label"},
+	{rule: "www.!ck", err: errors.New("wildcard or exception marker is not a whole label"), description: "Exception marker not leftmost"},
+	{rule: "co..uk", err: errors.New("rule has empty label"), description: "Empty label"},
+	{rule: "co.uk.", description: "Trailing dot"},
+	{rule: "co.uk..", err: errors.New("rule has empty label"), description: "Multiple trailing dots"},
+	{rule: ".", err: errors.New("rule has empty label"), description: "Trailing dot only"},
+	{rule: "exa_mple.com", err: errors.New("idna: disallowed rune U+005F"), description: "Invalid label"},
+	{rule: "-example.com", err: errors.New("idna: invalid label \"-example\""), description: "Leading hyphen"},
+	{rule: "xn--a.com", err: errors.New("idna: invalid label \"\\u0080\""), description: "Invalid punycode"},
+	{rule: "Example.COM", err: errors.New("idna: disallowed rune U+0045"), description: "Upper case"},
+}
+
+func TestValidateSuffixRule(t *testing.T) {
+	for _, test := range validateSuffixRuleTests {
+		err := ValidateSuffixRule(test.rule)
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v | %s", test.rule, err, test.err, test.description)
+		}
+	}
+}
+
+func TestValidateSuffixRuleAllRules(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	tldTrie, _ := trieConstruct(true, testPSLFilePath)
+	for _, rule := range trieRules(tldTrie).allSuffixes {
+		if err := ValidateSuffixRule(rule); err != nil {
+			t.Errorf("%q | Expected no error. Got %q", rule, err)
+		}
+	}
+}
+
+type parseSuffixRuleTest struct {
+	rule        string
+	labels      []string
+	isWildcard  bool
+	isException bool
+}
+
+var parseSuffixRuleTests = []parseSuffixRuleTest{
+	{rule: "", labels: nil},
+	{rule: "com", labels: []string{"com"}},
+	{rule: "co.uk", labels: []string{"co", "uk"}},
+	{rule: "co.uk.", labels: []string{"co", "uk"}},
+	{rule: "香港.hk", labels: []string{"香港", "hk"}},
+	{rule: "*.ck", labels: []string{"ck"}, isWildcard: true},
+	{rule: "*.kawasaki.jp", labels: []string{"kawasaki", "jp"}, isWildcard: true},
+	{rule: "!www.ck", labels: []string{"www", "ck"}, isException: true},
+	{rule: "!city.kawasaki.jp", labels: []string{"city", "kawasaki", "jp"}, isException: true},
+}
+
+func TestParseSuffixRule(t *testing.T) {
+	for _, test := range parseSuffixRuleTests {
+		labels, isWildcard, isException := ParseSuffixRule(test.rule)
+		if !reflect.DeepEqual(labels, test.labels) || isWildcard != test.isWildcard || isException != test.isException {
+			t.Errorf("%q | Output (%q, %t, %t) not equal to expected (%q, %t, %t)", test.rule,
+				labels, isWildcard, isException, test.labels, test.isWildcard, test.isException)
+		}
+	}
+}
+
+func TestTrieBuilder(t *testing.T) {
+	b, err := os.ReadFile(fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator)))
+	if err != nil {
+		t.Fatalf("Cannot read mini public suffix list | %q", err)
+	}
+
+	for _, includePrivateSuffix := range []bool{false, true} {
+		builder := NewTrieBuilder(includePrivateSuffix)
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for scanner.Scan() {
+			builder.Add(scanner.Text())
+		}
+		extractor := builder.Build()
+
+		expected, _ := New(SuffixListParams{CacheFilePath: fmt.Sprintf("test%smini_public_suffix_list.dat", string(os.PathSeparator)),
+			IncludePrivateSuffix: includePrivateSuffix})
+		for _, url := range []string{"https://www.example.com.ac", "https://a.b.ck", "https://www.ck", "https://example.org.sg",
+			"https://example.blogspot.com", "https://example.com"} {
+			res, err := extractor.Extract(URLParams{URL: url})
+			expectedRes, expectedErr := expected.Extract(URLParams{URL: url})
+			if !reflect.DeepEqual(res, expectedRes) || (err == nil) != (expectedErr == nil) {
+				t.Errorf("%q | Output %+v (%v) not equal to expected %+v (%v)", url, res, err, expectedRes, expectedErr)
+			}
+		}
+	}
+
+	builder := NewTrieBuilder(false)
+	for _, rule := range []string{"uk", "co.uk", "jp", "*.kawasaki.jp", "!city.kawasaki.jp"} {
+		builder.Add(rule)
+	}
+	extractor := builder.Build()
+	res, _ := extractor.Extract(URLParams{URL: "https://www.example.co.uk"})
+	if res.Suffix != "co.uk" || res.SuffixSection != ICANNSection {
+		t.Errorf("Expected Suffix co.uk in ICANN section. Got %q in section %d.", res.Suffix, res.SuffixSection)
+	}
+	res, _ = extractor.Extract(URLParams{URL: "https://example.foo.kawasaki.jp"})
+	if res.Suffix != "foo.kawasaki.jp" {
+		t.Errorf("Expected Suffix foo.kawasaki.jp. Got %q.", res.Suffix)
+	}
+	res, _ = extractor.Extract(URLParams{URL: "https://example.city.kawasaki.jp"})
+	if res.Suffix != "kawasaki.jp" {
+		t.Errorf("Expected Suffix kawasaki.jp. Got %q.", res.Suffix)
+	}
+	if res, err := extractor.Extract(URLParams{URL: "https://example.com"}); res.Suffix != "" || err != nil {
+		t.Errorf("Expected no Suffix. Got %q (%v).", res.Suffix, err)
+	}
+}
+
+func TestWalkLimit(t *testing.T) {
+	labels := make([]string, maxWalkSteps+1)
+	for i := range labels {
+		labels[i] = "l" + strconv.Itoa(i)
+	}
+	builder := NewTrieBuilder(false)
+	builder.Add(labels[len(labels)-1])
+	builder.Add(strings.Join(labels, "."))
+	extractor := builder.Build()
+
+	for _, test := range []struct {
+		url string
+		err error
+	}{
+		{"https://example." + strings.Join(labels[1:], "."), nil},
+		{"https://example." + strings.Join(labels, "."), ErrWalkLimitExceeded},
+		{"https://www.example." + strings.Join(labels, "."), ErrWalkLimitExceeded},
+	} {
+		if _, err := extractor.Extract(URLParams{URL: test.url}); err != test.err {
+			t.Errorf("%q | Error %v not equal to expected error %v", test.url, err, test.err)
+		}
+	}
+	if count := extractor.WalkLimitExceededCount(); count != 2 {
+		t.Errorf("Expected WalkLimitExceededCount 2. Got %d.", count)
+	}
+}
+
+func TestSuffixSectionPrecedence(t *testing.T) {
+	lines := []string{"com", "example.com", "*.ck", "// ===BEGIN PRIVATE DOMAINS===", "example.com", "*.ck", "blogspot.com"}
+	for _, test := range []struct {
+		includePrivateSuffix bool
+		url                  string
+		suffix               string
+		section              SuffixSection
+	}{
+		{false, "https://www.example.com", "example.com", ICANNSection},
+		{true, "https://www.example.com", "example.com", PrivateSection},
+		{false, "https://www.foo.ck", "foo.ck", ICANNSection},
+		{true, "https://www.foo.ck", "foo.ck", PrivateSection},
+		{true, "https://www.blogspot.com", "blogspot.com", PrivateSection},
+		{true, "https://www.google.com", "com", ICANNSection},
+	} {
+		builder := NewTrieBuilder(test.includePrivateSuffix)
+		for _, line := range lines {
+			builder.Add(line)
+		}
+		res, _ := builder.Build().Extract(URLParams{URL: test.url})
+		if res.Suffix != test.suffix || res.SuffixSection != test.section {
+			t.Errorf("%q | Output Suffix %q in section %d not equal to expected Suffix %q in section %d",
+				test.url, res.Suffix, res.SuffixSection, test.suffix, test.section)
+		}
+	}
+}
+
+func TestAddRemoveSuffix(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range []struct {
+		add, remove string
+		url         string
+		domain      string
+		suffix      string
+		section     SuffixSection
+		hasError    bool
+	}{
+		{url: "https://www.example.corp.internal", domain: "internal"},
+		{add: "corp.internal", url: "https://www.example.corp.internal", domain: "example", suffix: "corp.internal", section: PrivateSection},
+		{add: "// comment", url: "https://www.example.corp.internal", domain: "example", suffix: "corp.internal", section: PrivateSection, hasError: true},
+		{add: "a*.internal", url: "https://a.internal", domain: "internal", hasError: true},
+		{add: "!internal", url: "https://www.internal", domain: "internal", hasError: true},
+		{remove: "*.corp.*", url: "https://www.example.corp.internal", domain: "example", suffix: "corp.internal", section: PrivateSection, hasError: true},
+		{remove: "corp.internal", url: "https://www.example.corp.internal", domain: "internal"},
+		{add: "*.corp", url: "https://a.b.corp", domain: "a", suffix: "b.corp", section: PrivateSection},
+		{add: "!www.corp", url: "https://www.corp", domain: "www", suffix: "corp", section: PrivateSection},
+		{remove: "!www.corp", url: "https://www.corp", suffix: "www.corp", section: PrivateSection},
+		{add: "!www.corp", url: "https://www.corp", domain: "www", suffix: "corp", section: PrivateSection},
+		{remove: "*.corp", url: "https://a.b.corp", domain: "corp"},
+		{remove: "!www.corp", url: "https://www.corp", domain: "corp"},
+		{remove: "co.uk", url: "https://www.example.co.uk", domain: "co", suffix: "uk", section: ICANNSection},
+		{remove: "co.uk", url: "https://www.example.co.uk", domain: "co", suffix: "uk", section: ICANNSection},
+		{add: "co.uk", url: "https://www.example.co.uk", domain: "example", suffix: "co.uk", section: PrivateSection},
+		{remove: "公司.cn", url: "https://www.example.xn--55qx5d.cn", domain: "xn--55qx5d", suffix: "cn", section: ICANNSection},
+		{url: "https://www.example.公司.cn", domain: "公司", suffix: "cn", section: ICANNSection},
+		{add: "公司.cn", url: "https://www.example.公司.cn", domain: "example", suffix: "公司.cn", section: PrivateSection},
+	} {
+		var err error
+		if len(test.add) != 0 {
+			err = extractor.AddSuffix(test.add)
+		}
+		if len(test.remove) != 0 {
+			err = extractor.RemoveSuffix(test.remove)
+		}
+		if (err != nil) != test.hasError {
+			t.Errorf("(add %q, remove %q) | Expected error %t. Got %v", test.add, test.remove, test.hasError, err)
+		}
+		res, _ := extractor.Extract(URLParams{URL: test.url})
+		if res.Domain != test.domain || res.Suffix != test.suffix || res.SuffixSection != test.section {
+			t.Errorf("%q (add %q, remove %q) | Output Domain %q Suffix %q in section %d not equal to expected Domain %q Suffix %q in section %d",
+				test.url, test.add, test.remove, res.Domain, res.Suffix, res.SuffixSection, test.domain, test.suffix, test.section)
+		}
+	}
+	for _, label := range []string{"internal", "corp"} {
+		if _, ok := extractor.tldTrie.matches.Get(label); ok {
+			t.Errorf("Trie node %q should have been removed", label)
+		}
+	}
+}
+
+func TestAddRemoveSuffixSection(t *testing.T) {
+	builder := NewTrieBuilder(false)
+	builder.Add("a.foo")
+	extractor := builder.Build()
+
+	// parent of a PRIVATE top level wildcard rule is a PRIVATE section eTLD
+	extractor.AddSuffix("*.foo")
+	if suffix, isICANN := extractor.PublicSuffix("foo"); suffix != "foo" || isICANN {
+		t.Errorf("Expected PRIVATE section suffix %q. Got %q with isICANN = %t", "foo", suffix, isICANN)
+	}
+	if res, _ := extractor.Extract(URLParams{URL: "https://www.example.foo"}); res.Suffix != "example.foo" || res.SuffixSection != PrivateSection {
+		t.Errorf("Expected Suffix %q in PRIVATE section. Got %q in section %d", "example.foo", res.Suffix, res.SuffixSection)
+	}
+
+	// nodes no longer on any ICANN section path are private
+	extractor.AddSuffix("b.a.foo")
+	extractor.RemoveSuffix("a.foo")
+	foo, _ := extractor.tldTrie.matches.Get("foo")
+	a, _ := foo.matches.Get("a")
+	if !foo.private || !a.private {
+		t.Errorf("Expected nodes %q and %q to be private after removing ICANN section rule", "foo", "a.foo")
+	}
+
+	extractor.RemoveSuffix("*.foo")
+	if suffix, _ := extractor.PublicSuffix("foo"); suffix != "" {
+		t.Errorf("Expected no suffix after removing wildcard rule. Got %q", suffix)
+	}
+}
+
+func TestAddRemoveSuffixConcurrent(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				res, _ := extractor.Extract(URLParams{URL: "https://www.example.corp.internal"})
+				if res.Suffix != "" && res.Suffix != "corp.internal" {
+					t.Errorf("Unexpected Suffix %q", res.Suffix)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 1000; j++ {
+		extractor.AddSuffix("corp.internal")
+		extractor.RemoveSuffix("corp.internal")
+	}
+	wg.Wait()
+}
+
+func TestWildcardResolverModifiesSuffixList(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	resolvers := []func(base, label string) bool{
+		func(base, label string) bool {
+			extractor.AddSuffix(label + ".internal")
+			return false
+		},
+		func(base, label string) bool { panic("resolver failed") },
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, resolver := range resolvers {
+			func() {
+				defer func() { recover() }()
+				res, _ := extractor.Extract(URLParams{URL: "https://www.example.ck", WildcardResolver: resolver})
+				if res.Suffix != "ck" {
+					t.Errorf("Expected Suffix %q for rejected label. Got %q", "ck", res.Suffix)
+				}
+			}()
+			// the suffix list lock is released after the resolver returns or panics
+			extractor.RemoveSuffix("example.internal")
+			extractor.AddSuffix("example.internal")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("WildcardResolver modifying the suffix list deadlocked")
+	}
+	if res, _ := extractor.Extract(URLParams{URL: "https://www.example.internal"}); res.Suffix != "example.internal" {
+		t.Errorf("Expected suffix added by WildcardResolver to be matched. Got %+v", res)
+	}
+}
+
+func TestWildcardResolverRemovesRule(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	resolver := func(base, label string) bool {
+		extractor.RemoveSuffix("*." + base)
+		return true
+	}
+	// the rule matched before the resolver was called is used
+	res, _ := extractor.Extract(URLParams{URL: "https://www.example.ck", WildcardResolver: resolver, InternSuffixes: true})
+	if res.Suffix != "example.ck" || res.SuffixSection != ICANNSection {
+		t.Errorf("Expected Suffix %q in ICANN section. Got %q in section %d", "example.ck", res.Suffix, res.SuffixSection)
+	}
+	if res, _ := extractor.Extract(URLParams{URL: "https://www.example.ck"}); res.Suffix == "example.ck" {
+		t.Errorf("Expected wildcard rule removed by WildcardResolver to no longer match")
+	}
+}
+
+type suffixSourceLineTest struct {
+	includePrivateSuffix bool
+	suffix               string
+	line                 int
+}
+
+var suffixSourceLineTests = []suffixSourceLineTest{
+	{suffix: "ac", line: 2},
+	{suffix: "com.ac", line: 3},
+	{suffix: "COM.AC.", line: 3},
+	{suffix: "com。ac", line: 3},
+	{suffix: "org.sg", line: 11},
+	{suffix: "foo.ck", line: 9},
+	{suffix: "ck", line: 9},
+	{suffix: "www.ck", line: -1},
+	{suffix: "a.foo.ck", line: -1},
+	{suffix: "blogspot.com", line: -1},
+	{includePrivateSuffix: true, suffix: "blogspot.com", line: 14},
+	{suffix: "sg", line: -1},
+	{suffix: "example.com", line: -1},
+	{suffix: "", line: -1},
+}
+
+func TestSuffixSourceLine(t *testing.T) {
+	testPSLFilePath, ok := getCurrentFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	testPSLFilePath += string(os.PathSeparator) + "test" + string(os.PathSeparator) + "mini_public_suffix_list.dat"
+	for _, test := range suffixSourceLineTests {
+		extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath, IncludePrivateSuffix: test.includePrivateSuffix})
+		if line := extractor.SuffixSourceLine(test.suffix); line != test.line {
+			t.Errorf("%q | Output %d not equal to expected %d", test.suffix, line, test.line)
+		}
+	}
+
+	// Unicode and punycode forms of a rule are on the same line
+	builder := NewTrieBuilder(false)
+	for _, line := range []string{"// comment", "", "com", "公司.cn", "*.kawasaki.jp", "!city.kawasaki.jp"} {
+		builder.Add(line)
+	}
+	extractor := builder.Build()
+	for suffix, expected := range map[string]int{"com": 3, "公司.cn": 4, "xn--55qx5d.cn": 4, "cn": -1, "foo.kawasaki.jp": 5,
+		"city.kawasaki.jp": -1, "kawasaki.jp": -1} {
+		if line := extractor.SuffixSourceLine(suffix); line != expected {
+			t.Errorf("%q | Output %d not equal to expected %d", suffix, line, expected)
+		}
+	}
+}
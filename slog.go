@@ -0,0 +1,36 @@
+//go:build go1.21
+
+package fasttld
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, so that logging r with log/slog emits its
+// components as a group of attributes. Empty components are omitted.
+//
+// UserInfo is omitted as it may contain credentials.
+func (r *ExtractResult) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 9)
+	for _, attr := range [...]struct{ key, value string }{
+		{"scheme", r.Scheme},
+		{"host", r.Host()},
+		{"subdomain", r.SubDomain},
+		{"domain", r.Domain},
+		{"suffix", r.Suffix},
+		{"registered_domain", r.RegisteredDomain},
+		{"port", r.Port},
+		{"path", r.Path},
+	} {
+		if len(attr.value) != 0 {
+			attrs = append(attrs, slog.String(attr.key, attr.value))
+		}
+	}
+	switch r.HostType {
+	case HostName:
+		attrs = append(attrs, slog.String("host_type", "hostname"))
+	case IPv4:
+		attrs = append(attrs, slog.String("host_type", "ipv4 address"))
+	case IPv6:
+		attrs = append(attrs, slog.String("host_type", "ipv6 address"))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,154 @@
+package fasttld
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNotHostname is returned by ExtractSNI() if the SNI value is not a plain hostname,
+// e.g. if it has a scheme, port, path or trailing dot, or is an IP address.
+var ErrNotHostname = errors.New("not a hostname")
+
+// notHostnameChars are characters which cannot appear in a plain hostname
+const notHostnameChars string = endOfHostDelimiters + "@[]"
+
+var notHostnameCharsSet asciiSet = makeASCIISet(notHostnameChars)
+
+const blobScheme string = "blob:"
+
+const javaScriptScheme string = "javascript:"
+
+// javaScriptQuotes are the delimiters of JavaScript string literals
+const javaScriptQuotes string = "'\"`"
+
+// hasBlobScheme reports whether s begins with the blob: scheme, ignoring case.
+func hasBlobScheme(s string) bool {
+	return len(s) >= len(blobScheme) && strings.EqualFold(s[0:len(blobScheme)], blobScheme)
+}
+
+// intentScheme returns the value of the scheme parameter in the fragment of
+// an Android intent URL Path, e.g. "https" for "/path#Intent;scheme=https;end".
+//
+// Returns an empty string if there is no scheme parameter.
+func intentScheme(path string) string {
+	hashIdx := strings.IndexByte(path, '#')
+	if hashIdx == -1 {
+		return ""
+	}
+	params := path[hashIdx+1:]
+	if !strings.HasPrefix(params, "Intent;") {
+		return ""
+	}
+	for _, param := range strings.Split(params[len("Intent;"):], ";") {
+		if strings.HasPrefix(param, "scheme=") {
+			return param[len("scheme="):]
+		}
+	}
+	return ""
+}
+
+// ExtractSNI extracts components from the hostname in a TLS Server Name Indication (SNI) value.
+//
+// Returns ErrNotHostname if sni is not a plain hostname, as IETF RFC 6066 does not permit ports,
+// IP addresses or trailing dots in SNI values.
+//
+// If requireALabels = true, reject sni if it is not in ASCII (i.e. IDN labels must be punycode A-labels).
+func (f *FastTLD) ExtractSNI(sni string, requireALabels bool) (ExtractResult, error) {
+	lastRune, _ := utf8.DecodeLastRuneInString(sni)
+	if len(sni) == 0 || labelSeparatorsRuneSet.Exists(lastRune) ||
+		indexAnyASCII(sni, notHostnameCharsSet) != -1 || indexAny(sni, whitespaceRuneSet) != -1 {
+		return ExtractResult{}, ErrNotHostname
+	}
+	if requireALabels {
+		for i := 0; i < len(sni); i++ {
+			if sni[i] >= 0x80 {
+				return ExtractResult{}, errors.New("SNI has non-ASCII characters")
+			}
+		}
+	}
+	res, err := f.Extract(URLParams{URL: sni})
+	if err != nil {
+		return res, err
+	}
+	if res.HostType != HostName {
+		return ExtractResult{}, ErrNotHostname
+	}
+	return res, nil
+}
+
+// ErrInvalidPin is returned by ExtractPin() if the pin is not a hostname with an optional port.
+var ErrInvalidPin = errors.New("pin is not a hostname with an optional port")
+
+// ExtractPin extracts components from the host of a certificate pinning configuration line,
+// which is a hostname optionally followed by a port, e.g. "www.example.com:443".
+//
+// Returns ErrInvalidPin if pin has a scheme, userinfo, path or whitespace, and an error if
+// the hostname has no registered domain (e.g. it is an IP address or a public suffix).
+func (f *FastTLD) ExtractPin(pin string) (ExtractResult, error) {
+	if indexAny(pin, whitespaceRuneSet) != -1 {
+		return ExtractResult{}, ErrInvalidPin
+	}
+	res, err := f.Extract(URLParams{URL: pin})
+	if err != nil {
+		return res, err
+	}
+	if len(res.Scheme) != 0 || len(res.UserInfo) != 0 || len(res.Path) != 0 {
+		return ExtractResult{}, ErrInvalidPin
+	}
+	if res.HostType != HostName || len(res.RegisteredDomain) == 0 {
+		return ExtractResult{}, errors.New("pin has no registered domain")
+	}
+	return res, nil
+}
+
+// ExtractReferer extracts components from the URL in an HTTP Referer header value,
+// which may be a full URL or an origin without a path (e.g. "https://example.com").
+//
+// Returns an empty ExtractResult if referer is empty, the "no-referrer" sentinel, or an invalid URL.
+func (f *FastTLD) ExtractReferer(referer string) ExtractResult {
+	referer = fastTrim(referer, whitespaceRuneSet, trimBoth)
+	if len(referer) == 0 || strings.EqualFold(referer, "no-referrer") {
+		return ExtractResult{}
+	}
+	return f.ExtractValue(referer)
+}
+
+// ExtractFromJavaScriptURL extracts components from the URLs quoted in a javascript: URL, in order,
+// e.g. from "http://evil.com" in javascript:window.location='http://evil.com'.
+//
+// This is a best-effort heuristic for security scanning, not a JavaScript parser. After the URL is
+// percent-decoded, each string literal quoted with ', " or ` that begins with a scheme (e.g. "https://")
+// or "//" is extracted. Escape sequences and string concatenation are not handled, and string literals
+// that cannot be extracted are skipped. Returns nil if s is not a javascript: URL.
+func (f *FastTLD) ExtractFromJavaScriptURL(s string) []ExtractResult {
+	s = fastTrim(s, whitespaceRuneSet, trimBoth)
+	if len(s) < len(javaScriptScheme) || !strings.EqualFold(s[0:len(javaScriptScheme)], javaScriptScheme) {
+		return nil
+	}
+	script := s[len(javaScriptScheme):]
+	if unescaped, err := url.PathUnescape(script); err == nil {
+		script = unescaped
+	}
+	var results []ExtractResult
+	for {
+		startIdx := strings.IndexAny(script, javaScriptQuotes)
+		if startIdx == -1 {
+			break
+		}
+		endIdx := strings.IndexByte(script[startIdx+1:], script[startIdx])
+		if endIdx == -1 {
+			break
+		}
+		literal := script[startIdx+1 : startIdx+1+endIdx]
+		script = script[startIdx+1+endIdx+1:]
+		if getSchemeEndIndex(literal) == -1 {
+			continue
+		}
+		if res, err := f.Extract(URLParams{URL: literal}); err == nil {
+			results = append(results, res)
+		}
+	}
+	return results
+}
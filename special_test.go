@@ -0,0 +1,211 @@
+package fasttld
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type intentSchemeTest struct {
+	path     string
+	expected string
+}
+
+var intentSchemeTests = []intentSchemeTest{
+	{"", ""},
+	{"/path", ""},
+	{"/path#Intent;scheme=https;package=com.example.app;end", "https"},
+	{"#Intent;package=com.example.app;scheme=zxing;end", "zxing"},
+	{"/path?a=b#Intent;action=android.intent.action.VIEW;end", ""},
+	{"/path#intent;scheme=https;end", ""},
+	{"/path#Intent;scheme=;end", ""},
+	{"/path#Intent;S.browser_fallback_url=https%3A%2F%2Fexample.com;scheme=http;end", "http"},
+}
+
+func TestIntentScheme(t *testing.T) {
+	for _, test := range intentSchemeTests {
+		if output := intentScheme(test.path); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.path, output, test.expected)
+		}
+	}
+}
+
+func TestHasBlobScheme(t *testing.T) {
+	for s, expected := range map[string]bool{
+		"":                        false,
+		"blob":                    false,
+		"blob:":                   true,
+		"blob:https://a.com/uuid": true,
+		"Blob:https://a.com/uuid": true,
+		"blob.example.com":        false,
+		"https://blob:x@a.com":    false,
+	} {
+		if output := hasBlobScheme(s); output != expected {
+			t.Errorf("%q | Output %t not equal to expected %t", s, output, expected)
+		}
+	}
+}
+
+type extractSNITest struct {
+	sni            string
+	requireALabels bool
+	expected       ExtractResult
+	err            error
+}
+
+var extractSNITests = []extractSNITest{
+	{sni: "www.example.co.uk", expected: ExtractResult{SubDomain: "www", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.co.uk", HostType: HostName}},
+	{sni: "www.example.co.uk", requireALabels: true, expected: ExtractResult{SubDomain: "www", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.co.uk", HostType: HostName}},
+	{sni: "www.xn--mnchen-3ya.de", requireALabels: true, expected: ExtractResult{SubDomain: "www", Domain: "xn--mnchen-3ya", Suffix: "de", SuffixSection: ICANNSection,
+		RegisteredDomain: "xn--mnchen-3ya.de", HostType: HostName}},
+	{sni: "www.münchen.de", expected: ExtractResult{SubDomain: "www", Domain: "münchen", Suffix: "de", SuffixSection: ICANNSection,
+		RegisteredDomain: "münchen.de", HostType: HostName}},
+	{sni: "www.münchen.de", requireALabels: true, err: errors.New("SNI has non-ASCII characters")},
+	{sni: "www.example.com:443", err: ErrNotHostname},
+	{sni: "https://www.example.com", err: ErrNotHostname},
+	{sni: "www.example.com/path", err: ErrNotHostname},
+	{sni: "user@www.example.com", err: ErrNotHostname},
+	{sni: "www.example.com.", err: ErrNotHostname},
+	{sni: "www.example.com\u3002", err: ErrNotHostname},
+	{sni: " www.example.com", err: ErrNotHostname},
+	{sni: "127.0.0.1", err: ErrNotHostname},
+	{sni: "[::1]", err: ErrNotHostname},
+	{sni: "", err: ErrNotHostname},
+	{sni: "com", expected: ExtractResult{Suffix: "com", SuffixSection: ICANNSection}, err: errors.New("empty domain")},
+}
+
+func TestExtractSNI(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range extractSNITests {
+		res, err := extractor.ExtractSNI(test.sni, test.requireALabels)
+		if output := reflect.DeepEqual(res, test.expected); !output {
+			t.Errorf("%q | Output %+v not equal to expected output %+v", test.sni, res, test.expected)
+		}
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v", test.sni, err, test.err)
+		}
+	}
+}
+
+type extractPinTest struct {
+	pin      string
+	expected ExtractResult
+	err      error
+}
+
+var extractPinTests = []extractPinTest{
+	{pin: "www.example.co.uk", expected: ExtractResult{SubDomain: "www", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.co.uk", HostType: HostName}},
+	{pin: "www.example.co.uk:443", expected: ExtractResult{SubDomain: "www", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.co.uk", Port: "443", HostType: HostName}},
+	{pin: "Example.COM:8443", expected: ExtractResult{Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", Port: "8443", HostType: HostName}},
+	{pin: "*.example.com", expected: ExtractResult{SubDomain: "*", Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", HostType: HostName}},
+	{pin: "https://www.example.com", err: ErrInvalidPin},
+	{pin: "//www.example.com", err: ErrInvalidPin},
+	{pin: "user@www.example.com", err: ErrInvalidPin},
+	{pin: "www.example.com/path", err: ErrInvalidPin},
+	{pin: "www.example.com:443/", err: ErrInvalidPin},
+	{pin: " www.example.com", err: ErrInvalidPin},
+	{pin: "www.example.com:443 sha256/AAAA", err: ErrInvalidPin},
+	{pin: "www.example.com:", err: errors.New("invalid port")},
+	{pin: "www.example.com:65536", err: errors.New("invalid port")},
+	{pin: "www.example!.com", err: errors.New("invalid characters in hostname")},
+	{pin: "", err: errors.New("empty domain")},
+	{pin: "co.uk", expected: ExtractResult{Suffix: "co.uk", SuffixSection: ICANNSection}, err: errors.New("empty domain")},
+	{pin: "localhost:443", err: errors.New("pin has no registered domain")},
+	{pin: "127.0.0.1:443", err: errors.New("pin has no registered domain")},
+	{pin: "[::1]:443", err: errors.New("pin has no registered domain")},
+}
+
+func TestExtractPin(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for _, test := range extractPinTests {
+		res, err := extractor.ExtractPin(test.pin)
+		if output := reflect.DeepEqual(res, test.expected); !output {
+			t.Errorf("%q | Output %+v not equal to expected output %+v", test.pin, res, test.expected)
+		}
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("%q | Error %v not equal to expected error %v", test.pin, err, test.err)
+		}
+	}
+}
+
+var extractRefererTests = map[string]ExtractResult{
+	"https://www.example.com": {Scheme: "https://", SubDomain: "www", Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", HostType: HostName},
+	"https://www.example.com:8443": {Scheme: "https://", SubDomain: "www", Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", Port: "8443", HostType: HostName},
+	"https://www.example.com/": {Scheme: "https://", SubDomain: "www", Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", Path: "/", HostType: HostName},
+	"https://www.example.com/a/b?c=d": {Scheme: "https://", SubDomain: "www", Domain: "example", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.com", Path: "/a/b?c=d", HostType: HostName},
+	" https://example.co.uk ": {Scheme: "https://", Domain: "example", Suffix: "co.uk", SuffixSection: ICANNSection,
+		RegisteredDomain: "example.co.uk", HostType: HostName},
+	"":                     {},
+	"  ":                   {},
+	"no-referrer":          {},
+	"No-Referrer":          {},
+	"https://example!.com": {},
+}
+
+func TestExtractReferer(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	for referer, expected := range extractRefererTests {
+		if output := extractor.ExtractReferer(referer); !reflect.DeepEqual(output, expected) {
+			t.Errorf("%q | Output %+v not equal to expected output %+v", referer, output, expected)
+		}
+	}
+}
+
+var extractFromJavaScriptURLTests = map[string][]string{
+	"javascript:window.location='http://evil.com'":                         {"evil.com"},
+	" JavaScript:location.href = \"https://www.evil.co.uk/login?x=1\"; ":   {"evil.co.uk"},
+	"javascript:window.location%3D%27https%3A%2F%2Fevil.com%2Fpath%27":     {"evil.com"},
+	"javascript:open(`//cdn.example.net/a.js`);location='http://evil.com'": {"example.net", "evil.com"},
+	"javascript:alert('hello');location='https://a.example.org:8080/'":     {"example.org"},
+	"javascript:location='http://[::1]:8080/'":                             {"::1"},
+	"javascript:location='http://evil.com":                                 nil,
+	"javascript:location='http://evil!.com'":                               nil,
+	"javascript:void(0)":                                                   nil,
+	"http://example.com/?q='http://evil.com'":                              nil,
+	"": nil,
+}
+
+func TestExtractFromJavaScriptURL(t *testing.T) {
+	testPSLFilePath, ok := getTestPSLFilePath()
+	if !ok {
+		t.Errorf("Cannot get path to current module file")
+	}
+	extractor, _ := New(SuffixListParams{CacheFilePath: testPSLFilePath})
+	expected := ExtractResult{Scheme: "http://", Domain: "evil", Suffix: "com", SuffixSection: ICANNSection,
+		RegisteredDomain: "evil.com", HostType: HostName}
+	if output := extractor.ExtractFromJavaScriptURL("javascript:window.location='http://evil.com'"); len(output) != 1 ||
+		!reflect.DeepEqual(output[0], expected) {
+		t.Errorf("Output %+v not equal to expected output %+v", output, []ExtractResult{expected})
+	}
+	for s, expected := range extractFromJavaScriptURLTests {
+		var output []string
+		for _, res := range extractor.ExtractFromJavaScriptURL(s) {
+			output = append(output, res.RegisteredDomain)
+		}
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("%q | Output %q not equal to expected %q", s, output, expected)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package fasttld
 
 import (
 	"log"
+	"sort"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/karlseguin/intset"
@@ -22,17 +24,29 @@ const controlChars string = "\u0000\u0001\u0002\u0003\u0004\u0005\u0006\u0007\u0
 const whitespace string = controlChars + " \u0085\u0086\u00a0\u1680\u200b\u200c\u200d\uFEFF"
 const invalidHostNameChars string = whitespace + "!\"#$&'()*+,/:;<=>?@[\\]^_`{|}~\u0378\u04c0\u06dd\u180e\u2025\u202e\u206b\u2183\u2a74\u2ff0\ufdd0\uff05\uff0f\uff1a\ufffa"
 
+// Lookalikes of "." that are not label separators, e.g. U+2024 ONE DOT LEADER
+const homoglyphSeparators string = "\u0701\u0702\u2024\ua4f8\ua60e\ufe52\U00010a50"
+
+// Unicode bidirectional control characters
+const bidiControlChars string = "\u061c\u200e\u200f\u202a\u202b\u202c\u202d\u202e\u2066\u2067\u2068\u2069"
+
 const endOfHostWithPortDelimiters string = `/\?#`
 const endOfHostDelimiters string = endOfHostWithPortDelimiters + ":"
 const invalidUserInfoChars string = endOfHostWithPortDelimiters + "[]"
 
+// reservedSeparatorChars delimit other URL components or are valid in labels,
+// and cannot be used as custom label separators
+const reservedSeparatorChars string = endOfHostDelimiters + "@[]%*-_"
+
 // asciiSet ---------------------------------------------------------------
 
 var numericSet asciiSet = makeASCIISet(numbers)
+var hexDigitSet asciiSet = makeASCIISet(numbers + "ABCDEFabcdef")
 var alphaNumericSet asciiSet = makeASCIISet(alphabets + numbers)
 var endOfHostWithPortDelimitersSet asciiSet = makeASCIISet(endOfHostWithPortDelimiters)
 var endOfHostDelimitersSet asciiSet = makeASCIISet(endOfHostDelimiters)
 var invalidUserInfoCharsSet asciiSet = makeASCIISet(invalidUserInfoChars)
+var controlCharsSet asciiSet = makeASCIISet(controlChars + "\u007f")
 
 var schemeFirstCharSet asciiSet = makeASCIISet(alphabets)
 var schemeRemainingCharSet asciiSet = makeASCIISet(alphabets + numbers + "+-.")
@@ -66,11 +80,22 @@ func (as *asciiSet) contains(c byte) bool {
 	return (as[c/32] & (1 << (c % 32))) != 0
 }
 
+// *strings.Replacer ------------------------------------------------------
+
+// labelSeparatorReplacer replaces all label separators with "."
+var labelSeparatorReplacer *strings.Replacer = strings.NewReplacer("\u3002", ".", "\uff0e", ".", "\uff61", ".")
+var tabNewlineReplacer *strings.Replacer = strings.NewReplacer("\t", "", "\n", "", "\r", "")
+var homoglyphSeparatorReplacer *strings.Replacer = strings.NewReplacer("\u0701", ".", "\u0702", ".", "\u2024", ".",
+	"\ua4f8", ".", "\ua60e", ".", "\ufe52", ".", "\U00010a50", ".")
+
 // *intset.Rune -----------------------------------------------------------
 
 var labelSeparatorsRuneSet *intset.Rune = makeRuneSet(labelSeparators)
 var whitespaceRuneSet *intset.Rune = makeRuneSet(whitespace)
 var invalidHostNameCharsRuneSet *intset.Rune = makeRuneSet(invalidHostNameChars)
+var bidiControlCharsRuneSet *intset.Rune = makeRuneSet(bidiControlChars)
+var homoglyphSeparatorsRuneSet *intset.Rune = makeRuneSet(homoglyphSeparators)
+var bestEffortTrimRuneSet *intset.Rune = makeRuneSet(labelSeparators + "-")
 
 // makeRuneSet converts a string to a set of unique runes
 func makeRuneSet(s string) (iset *intset.Rune) {
@@ -90,6 +115,31 @@ func makeRuneSet(s string) (iset *intset.Rune) {
 
 // ------------------------------------------------------------------------
 
+// opaqueSchemes are URL schemes without "//" after the colon, which are recognised by
+// getOpaqueSchemeEndIndex. The value is true if the rest of the URL has a host,
+// e.g. "mailto:user@example.com", or false if it is all Path, e.g. "tel:+1-201-555-0123".
+var opaqueSchemes = map[string]bool{
+	"mailto": true,
+	"sip":    true,
+	"sips":   true,
+	"xmpp":   true,
+	"sms":    false,
+	"tel":    false,
+}
+
+// getOpaqueSchemeEndIndex checks if string s begins with an opaque URL Scheme (see opaqueSchemes)
+// followed by ":", and returns the index after the colon. Returns -1 if no opaque Scheme exists.
+func getOpaqueSchemeEndIndex(s string) int {
+	colonIdx := strings.IndexByte(s, ':')
+	if colonIdx == -1 {
+		return -1
+	}
+	if _, ok := opaqueSchemes[toLowerASCII(s[0:colonIdx])]; !ok {
+		return -1
+	}
+	return colonIdx + 1
+}
+
 // getSchemeEndIndex checks if string s begins with a URL Scheme and
 // returns its last index. Returns -1 if no Scheme exists.
 func getSchemeEndIndex(s string) int {
@@ -196,6 +246,64 @@ func hasInvalidChars(s string) bool {
 	return false
 }
 
+// validLabelSeparators returns true if every rune in separators can be used as a custom label separator.
+func validLabelSeparators(separators string) bool {
+	for _, r := range separators {
+		if r == utf8.RuneError || unicode.IsLetter(r) || unicode.IsDigit(r) ||
+			whitespaceRuneSet.Exists(r) || strings.ContainsRune(reservedSeparatorChars, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// mapLabelSeparators replaces every rune of separators in host with ".".
+func mapLabelSeparators(host string, separators string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return '.'
+		}
+		return r
+	}, host)
+}
+
+// wildcardLabelLen returns the length of the leftmost label of host and the label separator
+// after it if the label is "*" (e.g. "*.example.com"), or 0 otherwise.
+func wildcardLabelLen(host string) int {
+	if len(host) < 2 || host[0] != '*' {
+		return 0
+	}
+	if r, size := utf8.DecodeRuneInString(host[1:]); labelSeparatorsRuneSet.Exists(r) {
+		return 1 + size
+	}
+	return 0
+}
+
+// indexAny returns the index of the first instance of any Unicode code
+// point from chars in s, or -1 if no Unicode code point from chars is
+// present in s.
+//
+// Similar to strings.IndexAny but skips input validation and uses *intset.Rune.
+func indexAny(s string, chars *intset.Rune) int {
+	for i, r := range s {
+		if chars.Exists(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// countAny returns the number of instances of Unicode code points from chars in s.
+func countAny(s string, chars *intset.Rune) int {
+	var count int
+	for _, r := range s {
+		if chars.Exists(r) {
+			count++
+		}
+	}
+	return count
+}
+
 // lastIndexAny returns the index of the last instance of any Unicode code
 // point from chars in s, or -1 if no Unicode code point from chars is
 // present in s.
@@ -212,6 +320,50 @@ func lastIndexAny(s string, chars *intset.Rune) int {
 	return -1
 }
 
+// toLowerASCII returns s with all ASCII letters mapped to their lower case.
+//
+// Non-ASCII runes are left untouched, so byte offsets in s remain valid in the result.
+// s is returned as-is without allocation if it has no upper case ASCII letters.
+func toLowerASCII(s string) string {
+	idx := -1
+	for i := 0; i < len(s); i++ {
+		if 'A' <= s[i] && s[i] <= 'Z' {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return s
+	}
+	b := []byte(s)
+	for i := idx; i < len(b); i++ {
+		if 'A' <= b[i] && b[i] <= 'Z' {
+			b[i] += 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// toLowerHost is like toLowerASCII, but leaves the hex digits of percent-escapes (e.g. "%A1")
+// in hostname s untouched, as they encode bytes rather than letters.
+func toLowerHost(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && hexDigitSet.contains(s[i+1]) && hexDigitSet.contains(s[i+2]) {
+			i += 2
+		} else if 'A' <= s[i] && s[i] <= 'Z' {
+			if b == nil {
+				b = []byte(s)
+			}
+			b[i] += 'a' - 'A'
+		}
+	}
+	if b == nil {
+		return s
+	}
+	return string(b)
+}
+
 // reverse reverses a slice of strings in-place.
 func reverse(input []string) {
 	for i, j := 0, len(input)-1; i < j; i, j = i+1, j-1 {
@@ -234,6 +386,14 @@ func sepSize(r byte) int {
 
 var idnaToPuny *idna.Profile = idna.New(idna.MapForLookup(), idna.Transitional(true), idna.BidiRule(), idna.CheckHyphens(true))
 
+// idnaBidiRule only checks labels against the IDNA Bidi Rule (IETF RFC 5893)
+var idnaBidiRule *idna.Profile = idna.New(idna.BidiRule(), idna.ValidateLabels(true))
+
+// idnaDNSName converts hostnames to punycode, rejecting hostnames which cannot be
+// resolved by DNS, e.g. with empty or overlong labels
+var idnaDNSName *idna.Profile = idna.New(idna.MapForLookup(), idna.Transitional(true), idna.BidiRule(),
+	idna.CheckHyphens(true), idna.VerifyDNSLength(true))
+
 // formatAsPunycode formats s as punycode.
 func formatAsPunycode(s string) string {
 	asPunyCode, err := idnaToPuny.ToASCII(s)
@@ -244,6 +404,99 @@ func formatAsPunycode(s string) string {
 	return asPunyCode
 }
 
+// formatLabelsAsPunycode formats each label in s as punycode,
+// preserving the label separators between them.
+func formatLabelsAsPunycode(s string) string {
+	var sb strings.Builder
+	var labelStartIdx int
+	for {
+		sepIdx := indexAny(s[labelStartIdx:], labelSeparatorsRuneSet)
+		labelEndIdx := len(s)
+		if sepIdx != -1 {
+			labelEndIdx = labelStartIdx + sepIdx
+		}
+		if labelEndIdx != labelStartIdx {
+			label := formatAsPunycode(s[labelStartIdx:labelEndIdx])
+			if len(label) == 0 {
+				return ""
+			}
+			sb.WriteString(label)
+		}
+		if sepIdx == -1 {
+			break
+		}
+		labelStartIdx = labelEndIdx + sepSize(s[labelEndIdx])
+		sb.WriteString(s[labelEndIdx:labelStartIdx])
+	}
+	return sb.String()
+}
+
+// formatLabelsAsUnicode converts each punycode label in s to Unicode,
+// preserving the label separators between them. Labels which cannot be converted are kept as-is.
+func formatLabelsAsUnicode(s string) string {
+	if !strings.Contains(s, "xn--") {
+		return s
+	}
+	var sb strings.Builder
+	var labelStartIdx int
+	for {
+		sepIdx := indexAny(s[labelStartIdx:], labelSeparatorsRuneSet)
+		labelEndIdx := len(s)
+		if sepIdx != -1 {
+			labelEndIdx = labelStartIdx + sepIdx
+		}
+		label := s[labelStartIdx:labelEndIdx]
+		if unicodeLabel, err := idna.Punycode.ToUnicode(label); err == nil {
+			label = unicodeLabel
+		}
+		sb.WriteString(label)
+		if sepIdx == -1 {
+			break
+		}
+		labelStartIdx = labelEndIdx + sepSize(s[labelEndIdx])
+		sb.WriteString(s[labelEndIdx:labelStartIdx])
+	}
+	return sb.String()
+}
+
+// isASCIIDigits returns true if s consists only of ASCII digits.
+func isASCIIDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !numericSet.contains(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// digitValue returns the value of Unicode decimal digit r (e.g. 3 for '٣'), or -1 if r is not a decimal digit.
+func digitValue(r rune) int {
+	// Unicode decimal digits are encoded in contiguous ranges from 0 to 9
+	for _, rng := range unicode.Nd.R16 {
+		if rune(rng.Lo) <= r && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if rune(rng.Lo) <= r && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10
+		}
+	}
+	return -1
+}
+
+// normalizeDigits maps Unicode decimal digits in s (e.g. Arabic-Indic digits) to ASCII digits.
+func normalizeDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf {
+			if d := digitValue(r); d != -1 {
+				return '0' + rune(d)
+			}
+		}
+		return r
+	}, s)
+}
+
 // indexLastByteBefore returns the index of the last instance of byte b
 // before any byte in notAfterCharsSet, otherwise -1
 func indexLastByteBefore(s string, b byte, notAfterCharsSet asciiSet) int {
@@ -305,3 +558,65 @@ func fastTrim(s string, charsToTrim *intset.Rune, mode trimMode) string {
 	}
 	return s[startIdx:endIdx]
 }
+
+// splitPath splits path into the path, the query after the first "?" before any "#",
+// and the fragment after the first "#". hasQuery and hasFragment report whether the
+// "?" and "#" delimiters are present, as the query and fragment may be empty.
+func splitPath(path string) (string, string, string, bool, bool) {
+	var query string
+	path, fragment, hasFragment := splitFragment(path)
+	queryStartIdx := strings.IndexByte(path, '?')
+	if queryStartIdx != -1 {
+		path, query = path[0:queryStartIdx], path[queryStartIdx+1:]
+	}
+	return path, query, fragment, queryStartIdx != -1, hasFragment
+}
+
+// splitFragment splits path into the path and query, and the fragment after the first "#".
+// hasFragment reports whether the "#" delimiter is present.
+func splitFragment(path string) (string, string, bool) {
+	if hashIdx := strings.IndexByte(path, '#'); hashIdx != -1 {
+		return path[0:hashIdx], path[hashIdx+1:], true
+	}
+	return path, "", false
+}
+
+// normalizePathBackslashes replaces backslashes with "/" in path, up to its query or fragment.
+func normalizePathBackslashes(path string) string {
+	pathEndIdx := strings.IndexAny(path, "?#")
+	if pathEndIdx == -1 {
+		pathEndIdx = len(path)
+	}
+	if strings.IndexByte(path[0:pathEndIdx], '\\') == -1 {
+		return path
+	}
+	return strings.ReplaceAll(path[0:pathEndIdx], "\\", "/") + path[pathEndIdx:]
+}
+
+// sortQueryParams sorts the query parameters in path by key,
+// preserving the relative order of parameters with the same key.
+//
+// Keys are compared without percent-decoding. The fragment, if any, is left unchanged.
+func sortQueryParams(path string) string {
+	// the query ends at the fragment, which may itself contain "?"
+	queryEndIdx := len(path)
+	if hashIdx := strings.IndexByte(path, '#'); hashIdx != -1 {
+		queryEndIdx = hashIdx
+	}
+	queryStartIdx := strings.IndexByte(path[0:queryEndIdx], '?')
+	if queryStartIdx == -1 {
+		return path
+	}
+	params := strings.Split(path[queryStartIdx+1:queryEndIdx], "&")
+	if len(params) < 2 {
+		return path
+	}
+	key := func(param string) string {
+		if eqIdx := strings.IndexByte(param, '='); eqIdx != -1 {
+			return param[0:eqIdx]
+		}
+		return param
+	}
+	sort.SliceStable(params, func(i, j int) bool { return key(params[i]) < key(params[j]) })
+	return path[0:queryStartIdx+1] + strings.Join(params, "&") + path[queryEndIdx:]
+}
@@ -28,6 +28,123 @@ func TestPunyCode(t *testing.T) {
 	}
 }
 
+var labelsPunyCodeTests = []punyCodeTest{
+	{"", ""},
+	{"google.com", "google.com"},
+	{"hello.世界.com", "hello.xn--rhqv96g.com"},
+	{"hello\uff0e世界\u3002com\uff61", "hello\uff0exn--rhqv96g\u3002com\uff61"},
+	{"ＨＥＬＬＯ\uff0eＣＯＭ", "hello\uff0ecom"},
+	{"hello\u3002\u3002com", "hello\u3002\u3002com"},
+	{"hello\u3002" + strings.Repeat("x", 65536) + "\uff00", ""}, // int32 overflow.
+}
+
+func TestLabelsPunyCode(t *testing.T) {
+	for _, test := range labelsPunyCodeTests {
+		converted := formatLabelsAsPunycode(test.url)
+		if output := reflect.DeepEqual(converted, test.expected); !output {
+			t.Errorf("Output %q not equal to expected %q", converted, test.expected)
+		}
+	}
+}
+
+var labelsUnicodeTests = []punyCodeTest{
+	{"", ""},
+	{"google.com", "google.com"},
+	{"hello.xn--rhqv96g.com", "hello.世界.com"},
+	{"hello\uff0exn--rhqv96g\u3002com\uff61", "hello\uff0e世界\u3002com\uff61"},
+	{"xn--rhqv96g\u3002\u3002com", "世界\u3002\u3002com"},
+	{"xn--0.xn--rhqv96g", "xn--0.世界"}, // invalid punycode label kept as-is
+}
+
+func TestLabelsUnicode(t *testing.T) {
+	for _, test := range labelsUnicodeTests {
+		converted := formatLabelsAsUnicode(test.url)
+		if output := reflect.DeepEqual(converted, test.expected); !output {
+			t.Errorf("Output %q not equal to expected %q", converted, test.expected)
+		}
+	}
+}
+
+func TestNormalizeDigits(t *testing.T) {
+	for _, test := range []struct{ s, expected string }{
+		{"", ""},
+		{"8080", "8080"},
+		{"٨٠٨٠", "8080"},               // Arabic-Indic
+		{"۴۴۳", "443"},                 // Extended Arabic-Indic
+		{"८०", "80"},                   // Devanagari
+		{"８０", "80"},                   // Fullwidth
+		{"\U0001d7e0\U0001d7ec", "80"}, // Mathematical double-struck and sans-serif
+		{"Ⅷ²a٨", "Ⅷ²a8"},               // not decimal digits
+		{"٩٩٩٩٩", "99999"},
+	} {
+		if output := normalizeDigits(test.s); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.s, output, test.expected)
+		}
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	for _, test := range []struct {
+		path, expectedPath, expectedQuery, expectedFragment string
+		expectedHasQuery, expectedHasFragment               bool
+	}{
+		{"", "", "", "", false, false},
+		{"/a/b", "/a/b", "", "", false, false},
+		{"/a?x=1&y=2#frag", "/a", "x=1&y=2", "frag", true, true},
+		{"?x=1", "", "x=1", "", true, false},
+		{"#frag", "", "", "frag", false, true},
+		{"/a#frag?x=1", "/a", "", "frag?x=1", false, true},
+		{"/a?x=1?y=2#f#g", "/a", "x=1?y=2", "f#g", true, true},
+		{"/a%3Fb?q=%23#%20", "/a%3Fb", "q=%23", "%20", true, true},
+		{"/a?#", "/a", "", "", true, true},
+		{"/a?", "/a", "", "", true, false},
+		{"/a#", "/a", "", "", false, true},
+	} {
+		path, query, fragment, hasQuery, hasFragment := splitPath(test.path)
+		if path != test.expectedPath || query != test.expectedQuery || fragment != test.expectedFragment ||
+			hasQuery != test.expectedHasQuery || hasFragment != test.expectedHasFragment {
+			t.Errorf("%q | Output (%q, %q, %q, %t, %t) not equal to expected (%q, %q, %q, %t, %t)", test.path,
+				path, query, fragment, hasQuery, hasFragment,
+				test.expectedPath, test.expectedQuery, test.expectedFragment, test.expectedHasQuery, test.expectedHasFragment)
+		}
+	}
+}
+
+func TestSplitFragment(t *testing.T) {
+	for _, test := range []struct {
+		path, expectedPath, expectedFragment string
+		expectedHasFragment                  bool
+	}{
+		{"", "", "", false},
+		{"/a?x=1", "/a?x=1", "", false},
+		{"#", "", "", true},
+		{"/#/dashboard?tab=2", "/", "/dashboard?tab=2", true},
+		{"/a?x=1#f#g", "/a?x=1", "f#g", true},
+		{"/a%23b#%23", "/a%23b", "%23", true},
+	} {
+		path, fragment, hasFragment := splitFragment(test.path)
+		if path != test.expectedPath || fragment != test.expectedFragment || hasFragment != test.expectedHasFragment {
+			t.Errorf("%q | Output (%q, %q, %t) not equal to expected (%q, %q, %t)", test.path,
+				path, fragment, hasFragment, test.expectedPath, test.expectedFragment, test.expectedHasFragment)
+		}
+	}
+}
+
+func TestNormalizePathBackslashes(t *testing.T) {
+	for _, test := range []struct{ path, expected string }{
+		{"", ""},
+		{"/a/b", "/a/b"},
+		{"\\a\\b", "/a/b"},
+		{"/a\\b?x=\\y#\\z", "/a/b?x=\\y#\\z"},
+		{"/a\\b#\\z?x", "/a/b#\\z?x"},
+		{"?\\", "?\\"},
+	} {
+		if output := normalizePathBackslashes(test.path); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.path, output, test.expected)
+		}
+	}
+}
+
 type reverseTest struct {
 	original []string
 	expected []string
@@ -78,3 +195,155 @@ func TestFastTrim(t *testing.T) {
 		}
 	}
 }
+
+func TestLastIndexAny(t *testing.T) {
+	ss := []string{"", "abc", "a.b", "a.b.", "a\u3002b", "a\uff0eb\uff61c",
+		"\x80", "a.\x80", "\x80.b", "\xe3\x80", "a\xe3\x80.b", "a.b\xe3\x80", "\xc0\xae", "\xff\xfe."}
+
+	for _, s := range ss {
+		expected := strings.LastIndexAny(s, labelSeparators)
+		if output := lastIndexAny(s, labelSeparatorsRuneSet); output != expected {
+			t.Errorf("%q | Output %d not equal to expected %d", s, output, expected)
+		}
+	}
+}
+
+func TestCountAny(t *testing.T) {
+	ss := []string{"", "abc", "a.b", "a.b.", "a\u3002b", "a\uff0eb\uff61c", "..", "\x80.b"}
+
+	for _, s := range ss {
+		var expected int
+		for _, r := range s {
+			if strings.ContainsRune(labelSeparators, r) {
+				expected++
+			}
+		}
+		if output := countAny(s, labelSeparatorsRuneSet); output != expected {
+			t.Errorf("%q | Output %d not equal to expected %d", s, output, expected)
+		}
+	}
+}
+
+type toLowerASCIITest struct {
+	s        string
+	expected string
+}
+
+var toLowerASCIITests = []toLowerASCIITest{
+	{"", ""},
+	{"example.com", "example.com"},
+	{"Example.COM", "example.com"},
+	{"Bücher.DE", "bücher.de"},
+	{"Ｇｏ.COM", "Ｇｏ.com"},
+}
+
+func TestToLowerASCII(t *testing.T) {
+	for _, test := range toLowerASCIITests {
+		if output := toLowerASCII(test.s); output != test.expected {
+			t.Errorf("Output %q not equal to expected %q", output, test.expected)
+		}
+	}
+}
+
+var toLowerHostTests = []toLowerASCIITest{
+	{"", ""},
+	{"Example.COM", "example.com"},
+	{"%A1%c1.Example.COM", "%A1%c1.example.com"},
+	{"%E4%BD%A0你.COM", "%E4%BD%A0你.com"},
+	{"%ZZ.COM", "%zz.com"},
+	{"A%A", "a%a"},
+	{"%41A", "%41a"},
+}
+
+func TestToLowerHost(t *testing.T) {
+	for _, test := range toLowerHostTests {
+		if output := toLowerHost(test.s); output != test.expected {
+			t.Errorf("Output %q not equal to expected %q", output, test.expected)
+		}
+	}
+}
+
+type sortQueryParamsTest struct {
+	path     string
+	expected string
+}
+
+var sortQueryParamsTests = []sortQueryParamsTest{
+	{"", ""},
+	{"/path", "/path"},
+	{"/path?", "/path?"},
+	{"/path?a=1", "/path?a=1"},
+	{"/path?b=2&a=1", "/path?a=1&b=2"},
+	{"?b=2&a=1", "?a=1&b=2"},
+	{"/path?b=2&a=1&b=1&a=3", "/path?a=1&a=3&b=2&b=1"},
+	{"/path?b&a=1&ab=2", "/path?a=1&ab=2&b"},
+	{"/path?b=2&a=1#c=3&a=4", "/path?a=1&b=2#c=3&a=4"},
+	{"/path#b=2&a=1", "/path#b=2&a=1"},
+	{"/#/dashboard?tab=2&a=1", "/#/dashboard?tab=2&a=1"},
+	{"/path?b=2&a=1#/route?d=4&c=3", "/path?a=1&b=2#/route?d=4&c=3"},
+	{"/path?b=%41&a=?", "/path?a=?&b=%41"},
+}
+
+func TestSortQueryParams(t *testing.T) {
+	for _, test := range sortQueryParamsTests {
+		if output := sortQueryParams(test.path); output != test.expected {
+			t.Errorf("Output %q not equal to expected %q", output, test.expected)
+		}
+	}
+}
+
+func TestWildcardLabelLen(t *testing.T) {
+	for s, expected := range map[string]int{
+		"":               0,
+		"*":              0,
+		"*.":             2,
+		"*.example.com":  2,
+		"*\u3002example": 4,
+		"**.example.com": 0,
+		"*x.example.com": 0,
+		"a.*.example":    0,
+	} {
+		if output := wildcardLabelLen(s); output != expected {
+			t.Errorf("%q | Output %d not equal to expected %d", s, output, expected)
+		}
+	}
+}
+
+func TestValidLabelSeparators(t *testing.T) {
+	for separators, expected := range map[string]bool{
+		"":       true,
+		"|":      true,
+		"|,;":    true,
+		"\u2024": true,
+		"a":      false,
+		"1":      false,
+		"é":      false,
+		" ":      false,
+		"/":      false,
+		":":      false,
+		"@":      false,
+		"%":      false,
+		"-":      false,
+		"|:":     false,
+		"\xff":   false,
+	} {
+		if output := validLabelSeparators(separators); output != expected {
+			t.Errorf("%q | Output %t not equal to expected %t", separators, output, expected)
+		}
+	}
+}
+
+func TestMapLabelSeparators(t *testing.T) {
+	for _, test := range []struct {
+		host, separators, expected string
+	}{
+		{"www|example|com", "|", "www.example.com"},
+		{"www|example,com", "|,", "www.example.com"},
+		{"www.example.com", "|", "www.example.com"},
+		{"www|example|com", "", "www|example|com"},
+	} {
+		if output := mapLabelSeparators(test.host, test.separators); output != test.expected {
+			t.Errorf("%q | Output %q not equal to expected %q", test.host, output, test.expected)
+		}
+	}
+}